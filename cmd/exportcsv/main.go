@@ -1,11 +1,20 @@
 package main
 
 import (
-	"encoding/csv"
-	"export-db-info/internal/db/mysql_internal"
+	"context"
+	_ "export-db-info/internal/db/drivers"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"export-db-info/pkg/csv"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 )
 
 func main() {
@@ -15,116 +24,894 @@ func main() {
 	//	log.Fatal("Error loading .env file")
 	//}
 
-	dbInfo, err := mysql_internal.GetDatabaseInfo()
-	if err != nil {
-		log.Fatalf("faild get db info: %v", err)
-	}
+	// Ctrl-C（SIGINT）・SIGTERM を受け取った場合は取得・書き込みを中断する
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	baseCsvDir := os.Getenv("CSV_DIRECTORY")
 	if baseCsvDir == "" {
 		log.Fatal("CSV_DIRECTORY environment variable is not set.")
 	}
 
-	// DBネームを仕様したい場合はコメントアウト（スプシにインポートする際に環境変数変更する必要あり）
-	//newDbDirPath := filepath.Join(baseCsvDir, dbInfo.Name)
+	// DB_DRIVERで指定されたDBエンジンからデータベース情報を取得
+	// DB_MULTI_SCHEMASを指定した場合は、一致したスキーマごとに取得する
+	cfg := inspector.ConfigFromEnv()
+	dbInfos, err := inspector.InspectAll(ctx, cfg)
+	if err != nil {
+		log.Fatalf("faild get db info: %v", err)
+	}
 
+	if err := export(ctx, baseCsvDir, dbInfos, len(cfg.MultiSchemas) > 0); err != nil {
+		log.Fatalf("Could not export CSV: %v", err)
+	}
+}
+
+// export は取得した情報を baseCsvDir 内の一時ディレクトリに書き込み、すべて書き終えた後に baseCsvDir の前回の出力と置き換えます。
+// 中断・失敗した場合は一時ディレクトリを削除するため、書きかけのCSVは baseCsvDir に残りません。
+func export(ctx context.Context, baseCsvDir string, dbInfos []*sql_model.DB, multiSchema bool) error {
 	// 出力ディレクトリの作成
-	_, err = createUniqueDir(baseCsvDir)
+	if err := os.MkdirAll(baseCsvDir, 0755); err != nil {
+		return err
+	}
+	stagingDir, err := os.MkdirTemp(baseCsvDir, ".export-")
 	if err != nil {
-		log.Fatalf("Could not create unique directory: %v", err)
+		return err
 	}
+	defer os.RemoveAll(stagingDir)
 
-	for _, table := range dbInfo.Tables {
-		// CSVファイルのパス
-		csvPath := fmt.Sprintf("%s/%s.csv", baseCsvDir, table.Name)
-
-		// CSVファイルの作成
-		csvFile, err := os.Create(csvPath)
-		if err != nil {
-			log.Fatalf("Could not create CSV file for table %s: %v", table.Name, err)
-		}
-
-		// CSVライターの作成
-		writer := csv.NewWriter(csvFile)
-
-		// ヘッダー行の書き込み
-		headers := []string{
-			"COLUMN_NAME",
-			"COLUMN_TYPE",
-			"IS_PRIMARY_KEY",
-			"IS_NULLABLE",
-			"IS_UNIQUE",
-			"IS_INDEX",
-			"IS_FOREiGN_KEY",
-			"FOREiGN_KEY_TABLE",
-			"FOREiGN_KEY_COLUMN",
-			"COMMENT",
-		}
-		if err := writer.Write(headers); err != nil {
-			log.Fatalf("Could not write headers to CSV for table %s: %v", table.Name, err)
-		}
-
-		for _, col := range table.Columns {
-			isPrimaryKey := "×"
-			isNullable := "×"
-			isUnique := "×"
-			isIndexed := "×"
-			isForeign := "×"
-
-			if col.IsNullable {
-				isNullable = "○"
+	if !multiSchema {
+		// 単一スキーマの場合は従来どおり出力ディレクトリ直下に書き込む
+		if err := exportDatabase(ctx, stagingDir, dbInfos[0]); err != nil {
+			return err
+		}
+	} else {
+		// スキーマごとにサブディレクトリへ書き込み、スキーマの一覧を meta/schemas.csv に書き込む
+		for _, dbInfo := range dbInfos {
+			if err := exportDatabase(ctx, filepath.Join(stagingDir, dbInfo.Name), dbInfo); err != nil {
+				return fmt.Errorf("%s: %w", dbInfo.Name, err)
 			}
-			if col.IsPrimaryKey {
-				isPrimaryKey = "○"
+		}
+		if err := writeSchemasCSV(stagingDir, dbInfos); err != nil {
+			return fmt.Errorf("could not write schemas CSV: %w", err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return replaceOutput(stagingDir, baseCsvDir)
+}
+
+// managedDirs は exportcsv が出力するサブディレクトリです。
+var managedDirs = []string{"meta", "views", "indexes", "foreign_keys", "checks", "partitions", "triggers"}
+
+// managedEntries は dir 内の exportcsv が出力したファイル・ディレクトリの名前を返します。
+// 直下の *.csv と managedDirs に加え、前回スキーマごとに出力した場合は meta/schemas.csv にあるスキーマのディレクトリを含みます。
+func managedEntries(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	managed := make(map[string]bool)
+	for _, name := range managedDirs {
+		managed[name] = true
+	}
+	schemas, err := csv.ReadFile(filepath.Join(dir, "meta", "schemas.csv"))
+	if err != nil {
+		return nil, err
+	}
+	for _, schema := range schemas {
+		if name := schema["SCHEMA_NAME"]; name != "" && !strings.HasPrefix(name, ".") {
+			managed[name] = true
+		}
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() && managed[name] || !entry.IsDir() && strings.HasSuffix(name, ".csv") {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// replaceOutput は dstDir の前回の出力を srcDir の出力で置き換えます。
+// 前回の出力を一時ディレクトリへ退避してから srcDir の内容を移動するため、今回出力しなかったファイル
+// （削除・除外したテーブルのCSVや、データがなくなった制約のCSVなど）は残りません。
+// 途中で失敗した場合は移動した出力を戻し、前回の出力を復元します。
+func replaceOutput(srcDir, dstDir string) (err error) {
+	oldNames, err := managedEntries(dstDir)
+	if err != nil {
+		return err
+	}
+	newEntries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+	backupDir, err := os.MkdirTemp(dstDir, ".previous-")
+	if err != nil {
+		return err
+	}
+
+	var backedUp, moved []string
+	defer func() {
+		if err == nil {
+			os.RemoveAll(backupDir)
+			return
+		}
+		for _, name := range moved {
+			os.Rename(filepath.Join(dstDir, name), filepath.Join(srcDir, name))
+		}
+		for _, name := range backedUp {
+			os.Rename(filepath.Join(backupDir, name), filepath.Join(dstDir, name))
+		}
+		os.Remove(backupDir)
+	}()
+
+	for _, name := range oldNames {
+		if err := os.Rename(filepath.Join(dstDir, name), filepath.Join(backupDir, name)); err != nil {
+			return err
+		}
+		backedUp = append(backedUp, name)
+	}
+	for _, entry := range newEntries {
+		name := entry.Name()
+		if err := os.Rename(filepath.Join(srcDir, name), filepath.Join(dstDir, name)); err != nil {
+			return err
+		}
+		moved = append(moved, name)
+	}
+	return nil
+}
+
+// exportDatabase は1つのデータベース（スキーマ）の情報を baseCsvDir 以下にCSVとして書き込みます。
+// テーブルごとに ctx を確認し、中断された場合はその時点で書き込みを止めます。
+func exportDatabase(ctx context.Context, baseCsvDir string, dbInfo *sql_model.DB) error {
+	for _, table := range dbInfo.Tables {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// カラム定義の書き込み
+		if err := writeColumnsCSV(baseCsvDir, table); err != nil {
+			return fmt.Errorf("could not write CSV file for table %s: %w", table.Name, err)
+		}
+
+		// インデックス情報の書き込み
+		if err := writeIndexesCSV(baseCsvDir, table); err != nil {
+			return fmt.Errorf("could not write indexes CSV for table %s: %w", table.Name, err)
+		}
+
+		// 外部キー制約の書き込み
+		if err := writeForeignKeysCSV(baseCsvDir, table); err != nil {
+			return fmt.Errorf("could not write foreign keys CSV for table %s: %w", table.Name, err)
+		}
+
+		// CHECK制約の書き込み
+		if err := writeChecksCSV(baseCsvDir, table); err != nil {
+			return fmt.Errorf("could not write checks CSV for table %s: %w", table.Name, err)
+		}
+
+		// パーティション構成の書き込み
+		if err := writePartitionsCSV(baseCsvDir, table); err != nil {
+			return fmt.Errorf("could not write partitions CSV for table %s: %w", table.Name, err)
+		}
+
+		// トリガーの書き込み
+		if err := writeTriggersCSV(baseCsvDir, table); err != nil {
+			return fmt.Errorf("could not write triggers CSV for table %s: %w", table.Name, err)
+		}
+	}
+
+	// テーブル一覧（テーブル単位の情報）の書き込み
+	if err := writeTablesCSV(baseCsvDir, dbInfo.Tables); err != nil {
+		return fmt.Errorf("could not write tables CSV: %w", err)
+	}
+
+	// ビューの書き込み
+	if err := writeViewsCSV(baseCsvDir, dbInfo.Views); err != nil {
+		return fmt.Errorf("could not write views CSV: %w", err)
+	}
+
+	// シーケンスの書き込み
+	if err := writeSequencesCSV(baseCsvDir, dbInfo.Sequences); err != nil {
+		return fmt.Errorf("could not write sequences CSV: %w", err)
+	}
+
+	// ストアドプロシージャ・ストアドファンクションの書き込み
+	if err := writeRoutinesCSV(baseCsvDir, dbInfo.Routines); err != nil {
+		return fmt.Errorf("could not write routines CSV: %w", err)
+	}
+
+	// イベントの書き込み
+	if err := writeEventsCSV(baseCsvDir, dbInfo.Events); err != nil {
+		return fmt.Errorf("could not write events CSV: %w", err)
+	}
+
+	// 権限の書き込み
+	if err := writeGrantsCSV(baseCsvDir, dbInfo.Grants); err != nil {
+		return fmt.Errorf("could not write grants CSV: %w", err)
+	}
+
+	// 使われていない・冗長なインデックスの書き込み
+	if err := writeIndexReportsCSV(baseCsvDir, dbInfo.IndexReports); err != nil {
+		return fmt.Errorf("could not write index report CSV: %w", err)
+	}
+	return nil
+}
+
+// writeColumnsCSV はテーブルのカラム定義を <テーブル名>.csv に書き込みます。
+func writeColumnsCSV(baseDir string, table *sql_model.Table) error {
+	// ヘッダー行
+	headers := []string{
+		"COLUMN_NAME",
+		"COLUMN_TYPE",
+		"IS_PRIMARY_KEY",
+		"IS_NULLABLE",
+		"IS_UNIQUE",
+		"IS_INDEX",
+		"IS_FOREiGN_KEY",
+		"FOREiGN_KEY_TABLE",
+		"FOREiGN_KEY_COLUMN",
+		"COMMENT",
+		"EXTRA",
+		"GENERATED",
+		"GENERATION_EXPRESSION",
+		"CHARACTER_SET_NAME",
+		"COLLATION_NAME",
+		"ENUM_VALUES",
+		"COLUMN_DEFAULT",
+		"DEFAULT_KIND",
+	}
+	// プロファイリングした場合は値の傾向の列を追加する
+	profiled := isProfiled(table.Columns)
+	if profiled {
+		headers = append(headers,
+			"PROFILE_SAMPLED_ROWS",
+			"PROFILE_PARTIAL",
+			"NULL_RATIO",
+			"DISTINCT_ESTIMATE",
+			"MIN_VALUE",
+			"MAX_VALUE",
+			"AVG_LENGTH",
+			"TOP_VALUES",
+		)
+	}
+
+	var records [][]string
+	for _, col := range table.Columns {
+		isPrimaryKey := "×"
+		isNullable := "×"
+		isUnique := "×"
+		isIndexed := "×"
+		isForeign := "×"
+
+		if col.IsNullable {
+			isNullable = "○"
+		}
+		if col.IsPrimaryKey {
+			isPrimaryKey = "○"
+		}
+		if col.IsUnique {
+			isUnique = "○"
+			// プレフィックスのみに対するユニーク制約はプレフィックス長を添える
+			if col.UniquePrefixLength > 0 {
+				isUnique = fmt.Sprintf("○(%d)", col.UniquePrefixLength)
 			}
-			if col.IsUnique {
-				isUnique = "○"
+		}
+		if col.IsIndexed {
+			isIndexed = "○"
+		}
+		if col.IsForeign {
+			isForeign = "○"
+		}
+
+		// デフォルト値なしと空文字列のデフォルト値を区別できるよう、DEFAULT_KIND に種類を出力する
+		var columnDefault string
+		defaultKind := "NONE"
+		if col.Default != nil {
+			columnDefault = *col.Default
+			defaultKind = "LITERAL"
+			if col.IsDefaultExpression {
+				defaultKind = "EXPRESSION"
 			}
-			if col.IsIndexed {
-				isIndexed = "○"
+		}
+
+		record := []string{
+			col.Name,
+			col.Type,
+			isPrimaryKey,
+			isNullable,
+			isUnique,
+			isIndexed,
+			isForeign,
+			col.ForeignKeyTable,
+			col.ForeignKeyColumn,
+			col.Comment,
+			col.Extra,
+			col.Generated,
+			col.Expression,
+			col.CharacterSet,
+			col.Collation,
+			strings.Join(col.EnumValues, ", "),
+			columnDefault,
+			defaultKind,
+		}
+		if profiled {
+			record = append(record, profileRecord(col.Profile)...)
+		}
+		records = append(records, record)
+	}
+
+	return csv.WriteFile(filepath.Join(baseDir, table.Name+".csv"), headers, records)
+}
+
+// isProfiled はいずれかのカラムの値の傾向を調べたかどうかを返します。
+func isProfiled(columns []*sql_model.Column) bool {
+	for _, col := range columns {
+		if col.Profile != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// profileRecord はカラムの値の傾向をCSVの列に変換します。出現回数の多い値は「値 (回数)」を「 | 」で区切って並べます。
+// 調べていないカラム（バイナリ型など）の場合は空の列を返します。
+func profileRecord(profile *sql_model.ColumnProfile) []string {
+	if profile == nil {
+		return make([]string, 8)
+	}
+	var topValues []string
+	for _, vc := range profile.TopValues {
+		topValues = append(topValues, fmt.Sprintf("%s (%d)", vc.Value, vc.Count))
+	}
+	return []string{
+		strconv.FormatInt(profile.SampledRows, 10),
+		mark(profile.IsPartial),
+		strconv.FormatFloat(profile.NullRatio, 'f', 4, 64),
+		strconv.FormatInt(profile.DistinctCount, 10),
+		profile.Min,
+		profile.Max,
+		strconv.FormatFloat(profile.AvgLength, 'f', 1, 64),
+		strings.Join(topValues, " | "),
+	}
+}
+
+// writeSchemasCSV はスキーマごとに出力した場合のスキーマの一覧を meta/schemas.csv に書き込みます。
+// 各スキーマのCSVはスキーマ名のサブディレクトリに書き込みます。
+func writeSchemasCSV(baseDir string, dbInfos []*sql_model.DB) error {
+	headers := []string{
+		"SCHEMA_NAME",
+		"TABLE_COUNT",
+		"VIEW_COUNT",
+		"ROUTINE_COUNT",
+	}
+
+	var records [][]string
+	for _, dbInfo := range dbInfos {
+		records = append(records, []string{
+			dbInfo.Name,
+			strconv.Itoa(len(dbInfo.Tables)),
+			strconv.Itoa(len(dbInfo.Views)),
+			strconv.Itoa(len(dbInfo.Routines)),
+		})
+	}
+
+	return csv.WriteFile(filepath.Join(baseDir, "meta", "schemas.csv"), headers, records)
+}
+
+// writeTablesCSV はテーブル単位の情報（コメント・エンジン・照合順序など）を meta/tables.csv に書き込みます。
+func writeTablesCSV(baseDir string, tables []*sql_model.Table) error {
+	headers := []string{
+		"TABLE_NAME",
+		"COMMENT",
+		"ENGINE",
+		"COLLATION",
+		"ROW_FORMAT",
+		"AUTO_INCREMENT",
+		"CREATE_TIME",
+		"UPDATE_TIME",
+		"TABLE_ROWS",
+		"DATA_LENGTH",
+		"INDEX_LENGTH",
+		"DATA_FREE",
+		"SYSTEM_VERSIONED",
+		"PERIODS",
+	}
+
+	var records [][]string
+	for _, table := range tables {
+		records = append(records, []string{
+			table.Name,
+			table.Comment,
+			table.Engine,
+			table.Collation,
+			table.RowFormat,
+			formatInt(table.AutoIncrement),
+			formatTime(table.CreateTime),
+			formatTime(table.UpdateTime),
+			formatInt(table.TableRows),
+			formatInt(table.DataLength),
+			formatInt(table.IndexLength),
+			formatInt(table.DataFree),
+			mark(table.SystemVersioned),
+			formatPeriods(table.Periods),
+		})
+	}
+
+	return csv.WriteFile(filepath.Join(baseDir, "meta", "tables.csv"), headers, records)
+}
+
+// formatPeriods はテーブルの期間を「SYSTEM_TIME(row_start, row_end)」の形式でカンマ区切りにします。
+func formatPeriods(periods []*sql_model.Period) string {
+	var s []string
+	for _, period := range periods {
+		s = append(s, fmt.Sprintf("%s(%s, %s)", period.Name, period.StartColumn, period.EndColumn))
+	}
+	return strings.Join(s, ",")
+}
+
+// writeViewsCSV はビューの一覧を meta/views.csv に、各ビューのカラム定義を views/<ビュー名>.csv に書き込みます。
+func writeViewsCSV(baseDir string, views []*sql_model.View) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	headers := []string{
+		"VIEW_NAME",
+		"COMMENT",
+		"SECURITY_TYPE",
+		"CHECK_OPTION",
+		"IS_UPDATABLE",
+		"REFERENCED_TABLES",
+		"VIEW_DEFINITION",
+	}
+
+	var records [][]string
+	for _, view := range views {
+		records = append(records, []string{
+			view.Name,
+			view.Comment,
+			view.SecurityType,
+			view.CheckOption,
+			mark(view.IsUpdatable),
+			strings.Join(view.ReferencedTables, ","),
+			view.Definition,
+		})
+
+		var columnRecords [][]string
+		for _, col := range view.Columns {
+			columnRecords = append(columnRecords, []string{col.Name, col.Type, mark(col.IsNullable), col.Comment})
+		}
+		columnHeaders := []string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COMMENT"}
+		if err := csv.WriteFile(filepath.Join(baseDir, "views", view.Name+".csv"), columnHeaders, columnRecords); err != nil {
+			return err
+		}
+	}
+
+	return csv.WriteFile(filepath.Join(baseDir, "meta", "views.csv"), headers, records)
+}
+
+// writeIndexesCSV はテーブルのインデックス情報を indexes/<テーブル名>.csv に書き込みます。
+// 複合インデックスは information_schema.STATISTICS と同様にカラムごとに1行とします。
+func writeIndexesCSV(baseDir string, table *sql_model.Table) error {
+	if len(table.Indexes) == 0 {
+		return nil
+	}
+
+	headers := []string{
+		"INDEX_NAME",
+		"SEQ_IN_INDEX",
+		"COLUMN_NAME",
+		"EXPRESSION",
+		"SUB_PART",
+		"SORT_ORDER",
+		"IS_PRIMARY",
+		"IS_UNIQUE",
+		"INDEX_TYPE",
+		"IS_VISIBLE",
+		"COMMENT",
+		"CONDITION",
+	}
+
+	var records [][]string
+	for _, idx := range table.Indexes {
+		for i, col := range idx.Columns {
+			var subPart string
+			if col.Length > 0 {
+				subPart = strconv.FormatInt(col.Length, 10)
 			}
-			if col.IsForeign {
-				isIndexed = "○"
+			sortOrder := "ASC"
+			if col.Descending {
+				sortOrder = "DESC"
 			}
-
-			record := []string{
+			records = append(records, []string{
+				idx.Name,
+				strconv.Itoa(i + 1),
 				col.Name,
-				col.Type,
-				isPrimaryKey,
-				isNullable,
-				isUnique,
-				isIndexed,
-				isForeign,
-				col.ForeignKeyTable,
-				col.ForeignKeyColumn,
-				col.Comment,
-			}
+				col.Expression,
+				subPart,
+				sortOrder,
+				mark(idx.IsPrimary),
+				mark(idx.IsUnique),
+				idx.Type,
+				mark(idx.IsVisible),
+				idx.Comment,
+				idx.Condition,
+			})
+		}
+	}
 
-			if err := writer.Write(record); err != nil {
-				log.Fatalf("Could not write row to CSV for column %s: %v", col.Name, err)
-			}
+	return csv.WriteFile(filepath.Join(baseDir, "indexes", table.Name+".csv"), headers, records)
+}
+
+// writeForeignKeysCSV はテーブルの外部キー制約を foreign_keys/<テーブル名>.csv に書き込みます。
+// 複合外部キーは information_schema.KEY_COLUMN_USAGE と同様にカラムごとに1行とします。
+func writeForeignKeysCSV(baseDir string, table *sql_model.Table) error {
+	if len(table.ForeignKeys) == 0 {
+		return nil
+	}
+
+	headers := []string{
+		"CONSTRAINT_NAME",
+		"ORDINAL_POSITION",
+		"COLUMN_NAME",
+		"REFERENCED_TABLE_NAME",
+		"REFERENCED_COLUMN_NAME",
+		"UPDATE_RULE",
+		"DELETE_RULE",
+	}
+
+	var records [][]string
+	for _, fk := range table.ForeignKeys {
+		for i, col := range fk.Columns {
+			records = append(records, []string{
+				fk.Name,
+				strconv.Itoa(i + 1),
+				col,
+				fk.ReferencedTable,
+				fk.ReferencedColumns[i],
+				fk.UpdateRule,
+				fk.DeleteRule,
+			})
 		}
-		// CSVライターをフラッシュ
-		writer.Flush()
+	}
+
+	return csv.WriteFile(filepath.Join(baseDir, "foreign_keys", table.Name+".csv"), headers, records)
+}
+
+// writeChecksCSV はテーブルのCHECK制約を checks/<テーブル名>.csv に書き込みます。
+func writeChecksCSV(baseDir string, table *sql_model.Table) error {
+	if len(table.Checks) == 0 {
+		return nil
+	}
+
+	headers := []string{
+		"CONSTRAINT_NAME",
+		"CHECK_CLAUSE",
+		"ENFORCED",
+	}
 
-		// CSVファイルをクローズ
-		csvFile.Close()
+	var records [][]string
+	for _, check := range table.Checks {
+		records = append(records, []string{
+			check.Name,
+			check.Expression,
+			mark(check.IsEnforced),
+		})
 	}
+
+	return csv.WriteFile(filepath.Join(baseDir, "checks", table.Name+".csv"), headers, records)
 }
 
-// createUniqueDir は指定されたベースディレクトリに対してユニークなディレクトリを作成します。
-func createUniqueDir(baseDir string) (string, error) {
-	dir := baseDir
-	for i := 1; ; i++ {
-		if _, err := os.Stat(dir); os.IsNotExist(err) {
-			// ディレクトリが存在しない場合、ディレクトリを作成
-			err := os.MkdirAll(dir, 0755)
-			if err != nil {
-				return "", err
-			}
-			return dir, nil
+// writePartitionsCSV はテーブルのパーティション構成を partitions/<テーブル名>.csv に書き込みます。
+// information_schema.PARTITIONS と同様にサブパーティションごとに1行とし、パーティション方式などは各行に繰り返し出力します。
+func writePartitionsCSV(baseDir string, table *sql_model.Table) error {
+	if table.Partitioning == nil {
+		return nil
+	}
+
+	headers := []string{
+		"PARTITION_NAME",
+		"SUBPARTITION_NAME",
+		"PARTITION_METHOD",
+		"PARTITION_EXPRESSION",
+		"SUBPARTITION_METHOD",
+		"SUBPARTITION_EXPRESSION",
+		"PARTITION_BOUND",
+		"TABLE_ROWS",
+		"PARTITION_COMMENT",
+	}
+
+	partitioning := table.Partitioning
+	var records [][]string
+	appendRecord := func(partition *sql_model.Partition, subpartition *sql_model.Partition) {
+		rows, subpartitionName := partition.Rows, ""
+		if subpartition != nil {
+			rows, subpartitionName = subpartition.Rows, subpartition.Name
+		}
+		records = append(records, []string{
+			partition.Name,
+			subpartitionName,
+			partitioning.Method,
+			partitioning.Expression,
+			partitioning.SubpartitionMethod,
+			partitioning.SubpartitionExpression,
+			partition.Bound,
+			formatInt(rows),
+			partition.Comment,
+		})
+	}
+	for _, partition := range partitioning.Partitions {
+		if len(partition.Subpartitions) == 0 {
+			appendRecord(partition, nil)
+		}
+		for _, subpartition := range partition.Subpartitions {
+			appendRecord(partition, subpartition)
+		}
+	}
+
+	return csv.WriteFile(filepath.Join(baseDir, "partitions", table.Name+".csv"), headers, records)
+}
+
+// writeTriggersCSV はテーブルのトリガーを triggers/<テーブル名>.csv に書き込みます。
+func writeTriggersCSV(baseDir string, table *sql_model.Table) error {
+	if len(table.Triggers) == 0 {
+		return nil
+	}
+
+	headers := []string{
+		"TRIGGER_NAME",
+		"ACTION_TIMING",
+		"EVENT_MANIPULATION",
+		"ACTION_ORDER",
+		"DEFINER",
+		"ACTION_STATEMENT",
+	}
+
+	var records [][]string
+	for _, trigger := range table.Triggers {
+		records = append(records, []string{
+			trigger.Name,
+			trigger.Timing,
+			trigger.Event,
+			strconv.FormatInt(trigger.Order, 10),
+			trigger.Definer,
+			trigger.Statement,
+		})
+	}
+
+	return csv.WriteFile(filepath.Join(baseDir, "triggers", table.Name+".csv"), headers, records)
+}
+
+// writeRoutinesCSV はルーチンの一覧を meta/routines.csv に、引数を meta/parameters.csv に書き込みます。
+// routines.csv の PARAMETERS には一覧で読みやすいよう「IN 名前 型」をカンマ区切りで並べます。
+func writeRoutinesCSV(baseDir string, routines []*sql_model.Routine) error {
+	if len(routines) == 0 {
+		return nil
+	}
+
+	headers := []string{
+		"ROUTINE_NAME",
+		"ROUTINE_TYPE",
+		"PARAMETERS",
+		"RETURN_TYPE",
+		"LANGUAGE",
+		"IS_DETERMINISTIC",
+		"SQL_DATA_ACCESS",
+		"SECURITY_TYPE",
+		"DEFINER",
+		"COMMENT",
+		"ROUTINE_DEFINITION",
+	}
+	parameterHeaders := []string{
+		"ROUTINE_NAME",
+		"ROUTINE_TYPE",
+		"ORDINAL_POSITION",
+		"PARAMETER_MODE",
+		"PARAMETER_NAME",
+		"DATA_TYPE",
+	}
+
+	var records, parameterRecords [][]string
+	for _, routine := range routines {
+		var params []string
+		for i, param := range routine.Parameters {
+			params = append(params, strings.TrimSpace(param.Mode+" "+param.Name+" "+param.DataType))
+			parameterRecords = append(parameterRecords, []string{
+				routine.Name,
+				routine.Type,
+				strconv.Itoa(i + 1),
+				param.Mode,
+				param.Name,
+				param.DataType,
+			})
 		}
-		// ディレクトリが存在する場合、新しい名前を生成
-		dir = fmt.Sprintf("%s_%d", baseDir, i)
+		records = append(records, []string{
+			routine.Name,
+			routine.Type,
+			strings.Join(params, ", "),
+			routine.ReturnType,
+			routine.Language,
+			mark(routine.IsDeterministic),
+			routine.DataAccess,
+			routine.SecurityType,
+			routine.Definer,
+			routine.Comment,
+			routine.Definition,
+		})
+	}
+
+	if err := csv.WriteFile(filepath.Join(baseDir, "meta", "parameters.csv"), parameterHeaders, parameterRecords); err != nil {
+		return err
+	}
+	return csv.WriteFile(filepath.Join(baseDir, "meta", "routines.csv"), headers, records)
+}
+
+// writeSequencesCSV はシーケンスの一覧を meta/sequences.csv に書き込みます。
+func writeSequencesCSV(baseDir string, sequences []*sql_model.Sequence) error {
+	if len(sequences) == 0 {
+		return nil
+	}
+
+	headers := []string{
+		"SEQUENCE_NAME",
+		"DATA_TYPE",
+		"START_VALUE",
+		"INCREMENT",
+		"MIN_VALUE",
+		"MAX_VALUE",
+		"CYCLE",
+		"OWNED_BY",
+	}
+
+	var records [][]string
+	for _, sequence := range sequences {
+		records = append(records, []string{
+			sequence.Name,
+			sequence.DataType,
+			strconv.FormatInt(sequence.Start, 10),
+			strconv.FormatInt(sequence.Increment, 10),
+			strconv.FormatInt(sequence.MinValue, 10),
+			strconv.FormatInt(sequence.MaxValue, 10),
+			mark(sequence.Cycle),
+			sequence.OwnedBy,
+		})
+	}
+
+	return csv.WriteFile(filepath.Join(baseDir, "meta", "sequences.csv"), headers, records)
+}
+
+// writeEventsCSV はイベントスケジューラのイベントを meta/events.csv に書き込みます。
+func writeEventsCSV(baseDir string, events []*sql_model.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	headers := []string{
+		"EVENT_NAME",
+		"EVENT_TYPE",
+		"EXECUTE_AT",
+		"INTERVAL_VALUE",
+		"INTERVAL_FIELD",
+		"STARTS",
+		"ENDS",
+		"STATUS",
+		"ON_COMPLETION",
+		"DEFINER",
+		"COMMENT",
+		"EVENT_DEFINITION",
+	}
+
+	var records [][]string
+	for _, event := range events {
+		records = append(records, []string{
+			event.Name,
+			event.Type,
+			formatTime(event.ExecuteAt),
+			event.IntervalValue,
+			event.IntervalField,
+			formatTime(event.Starts),
+			formatTime(event.Ends),
+			event.Status,
+			event.OnCompletion,
+			event.Definer,
+			event.Comment,
+			event.Definition,
+		})
+	}
+
+	return csv.WriteFile(filepath.Join(baseDir, "meta", "events.csv"), headers, records)
+}
+
+// writeGrantsCSV はユーザーの権限を meta/grants.csv に書き込みます。
+func writeGrantsCSV(baseDir string, grants []*sql_model.Grant) error {
+	if len(grants) == 0 {
+		return nil
+	}
+
+	headers := []string{
+		"GRANTEE",
+		"PRIVILEGE_LEVEL",
+		"TABLE_SCHEMA",
+		"TABLE_NAME",
+		"COLUMN_NAME",
+		"PRIVILEGE_TYPE",
+		"IS_GRANTABLE",
+	}
+
+	var records [][]string
+	for _, grant := range grants {
+		records = append(records, []string{
+			grant.Grantee,
+			grant.Level,
+			grant.Schema,
+			grant.Table,
+			grant.Column,
+			grant.Privilege,
+			mark(grant.IsGrantable),
+		})
+	}
+
+	return csv.WriteFile(filepath.Join(baseDir, "meta", "grants.csv"), headers, records)
+}
+
+// writeIndexReportsCSV は削除を検討できるインデックスを、削除するSQLとともに meta/index_report.csv に書き込みます。
+func writeIndexReportsCSV(baseDir string, reports []*sql_model.IndexReport) error {
+	if len(reports) == 0 {
+		return nil
+	}
+
+	headers := []string{
+		"TABLE_NAME",
+		"INDEX_NAME",
+		"REASON",
+		"INDEX_COLUMNS",
+		"DOMINANT_INDEX_NAME",
+		"DOMINANT_INDEX_COLUMNS",
+		"COUNT_READ",
+		"DROP_STATEMENT",
+	}
+
+	var records [][]string
+	for _, report := range reports {
+		records = append(records, []string{
+			report.Table,
+			report.Index,
+			report.Reason,
+			report.Columns,
+			report.DominantIndex,
+			report.DominantColumns,
+			formatInt(report.ReadCount),
+			report.DropStatement,
+		})
+	}
+
+	return csv.WriteFile(filepath.Join(baseDir, "meta", "index_report.csv"), headers, records)
+}
+
+// mark は真偽値をCSV出力用の「○」「×」に変換します。
+func mark(b bool) string {
+	if b {
+		return "○"
+	}
+	return "×"
+}
+
+// formatInt は整数をCSV出力用の文字列に変換します。nilの場合は空文字列を返します。
+func formatInt(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+// formatTime は日時をCSV出力用の文字列に変換します。nilの場合は空文字列を返します。
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
 	}
+	return t.Format("2006-01-02 15:04:05")
 }
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeFiles は dir 以下に files（相対パス）を空のファイルとして作成します。
+func writeFiles(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		content := ""
+		if name == filepath.Join("meta", "schemas.csv") {
+			content = "SCHEMA_NAME,TABLE_COUNT\nold_schema,1\n"
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// listFiles は dir 以下のファイルを相対パスで返します。
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// 前回の出力は今回の出力で丸ごと置き換え、exportcsv が出力していないファイルは残すこと
+func TestReplaceOutput(t *testing.T) {
+	dstDir := t.TempDir()
+	writeFiles(t, dstDir,
+		"dropped.csv",
+		"users.csv",
+		filepath.Join("checks", "users.csv"),
+		filepath.Join("meta", "grants.csv"),
+		filepath.Join("meta", "schemas.csv"),
+		filepath.Join("old_schema", "t.csv"),
+		"notes.txt",
+		filepath.Join("mine", "keep.csv"),
+	)
+	srcDir, err := os.MkdirTemp(dstDir, ".export-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, srcDir,
+		"users.csv",
+		filepath.Join("indexes", "users.csv"),
+		filepath.Join("meta", "tables.csv"),
+	)
+
+	if err := replaceOutput(srcDir, dstDir); err != nil {
+		t.Fatal(err)
+	}
+	os.RemoveAll(srcDir)
+
+	got := strings.Join(listFiles(t, dstDir), "\n")
+	want := strings.Join([]string{"indexes/users.csv", "meta/tables.csv", "mine/keep.csv", "notes.txt", "users.csv"}, "\n")
+	if got != want {
+		t.Errorf("files after replace:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// 移動に失敗した場合は前回の出力を復元すること
+func TestReplaceOutputRestoresOnFailure(t *testing.T) {
+	dstDir := t.TempDir()
+	writeFiles(t, dstDir, "users.csv", filepath.Join("meta", "tables.csv"), filepath.Join("conflict", "keep.txt"))
+	srcDir, err := os.MkdirTemp(dstDir, ".export-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 管理対象でない既存の空でないディレクトリと同名のディレクトリは移動できない
+	writeFiles(t, srcDir, "a.csv", filepath.Join("conflict", "x.csv"))
+
+	if err := replaceOutput(srcDir, dstDir); err == nil {
+		t.Fatal("replaceOutput succeeded, want error")
+	}
+	os.RemoveAll(srcDir)
+
+	got := strings.Join(listFiles(t, dstDir), "\n")
+	want := strings.Join([]string{"conflict/keep.txt", "meta/tables.csv", "users.csv"}, "\n")
+	if got != want {
+		t.Errorf("files after failed replace:\n%s\nwant:\n%s", got, want)
+	}
+}
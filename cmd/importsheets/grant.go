@@ -0,0 +1,210 @@
+package main
+
+import (
+	"export-db-info/internal/google_internal"
+	"export-db-info/internal/model/google_model"
+	"google.golang.org/api/sheets/v4"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// grantSheetName はユーザーの権限をまとめたシートの名前です。
+const grantSheetName = "権限"
+
+// grantMatrixColumnWidth は「テーブル権限一覧」のユーザーごとの列の幅（結合する列数）です。
+const grantMatrixColumnWidth = 2
+
+// tablePrivilegeOrder は「テーブル権限一覧」に表示するテーブルに対する権限とその表示順です。
+// グローバル・データベース単位の権限のうち、テーブルに関係しないもの（CREATE USER など）は表示しません。
+var tablePrivilegeOrder = []string{
+	"SELECT", "INSERT", "UPDATE", "DELETE", "REFERENCES", "INDEX", "ALTER", "DROP", "TRIGGER", "SHOW VIEW",
+}
+
+// schemaGrantSectionColumns は「グローバル・データベース権限一覧」セクションの列です。
+var schemaGrantSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"ユーザー", 3},
+	{"範囲", 1},
+	{"データベース", 2},
+	{"権限", 6},
+	{"付与可能", 1},
+}
+
+// createGrantSheet は meta/grants.csv と meta/tables.csv の内容から「権限」シートを作成します。
+// 権限を出力していない場合はシートを作成せずnilを返します。
+func createGrantSheet(sheSrv *sheets.Service, spreadsheetId string, csvDir string) *sheetEntry {
+	grants, err := readMetaCSVRows(csvDir, "grants.csv")
+	if err != nil {
+		log.Printf("Unable to read grants csv: %v", err)
+	}
+	if len(grants) == 0 {
+		return nil
+	}
+	tables, err := readMetaCSVRows(csvDir, "tables.csv")
+	if err != nil {
+		log.Printf("Unable to read tables csv: %v", err)
+	}
+
+	grantees := granteesOf(grants)
+	// ユーザーが多い場合に既定の列数（26列）を超えないよう、シートの列数を広げる
+	columnCount := int64(4 + grantMatrixColumnWidth*len(grantees))
+	if columnCount < 26 {
+		columnCount = 26
+	}
+
+	resp, err := sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{
+			AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{
+				Title:          grantSheetName,
+				GridProperties: &sheets.GridProperties{RowCount: 1000, ColumnCount: columnCount},
+			}},
+		}},
+	}).Do()
+	if err != nil {
+		log.Printf("Unable to create new sheet: %v", err)
+		return nil
+	}
+	if len(resp.Replies) == 0 || resp.Replies[0].AddSheet == nil {
+		log.Fatal("Failed to get the new sheet ID")
+	}
+	sheetId := resp.Replies[0].AddSheet.Properties.SheetId
+
+	_, err = sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: createGrantSheetRequests(sheetId, grantees, grants, tables),
+	}).Do()
+	if err != nil {
+		log.Printf("Unable to create new sheet: %v", err)
+	}
+
+	return &sheetEntry{name: grantSheetName, id: sheetId}
+}
+
+// createGrantSheetRequests は「権限」シートのレイアウトを作成します。
+// テーブルを行、ユーザーを列とした「テーブル権限一覧」の後ろに、グローバル・データベース単位の権限を並べます。
+func createGrantSheetRequests(sheetId int64, grantees []string, grants, tables []map[string]string) []*sheets.Request {
+	var requests []*sheets.Request
+
+	requests = append(requests, google_internal.CreateSheetLayoutRequest(
+		sheetId,
+		&google_model.RangeOption{StartRow: 0, EndRow: 2, StartCol: 0, EndCol: 3},
+		true,
+		"CENTER",
+		"MIDDLE",
+		routineTitleColor,
+		&sheets.Color{Red: 1, Green: 1, Blue: 1},
+		"権限一覧",
+		"",
+		&sheets.TextFormat{FontSize: 10, Bold: true},
+	)...)
+
+	columns := []sectionColumn{{"No", 1}, {"テーブル名", 3}}
+	for _, grantee := range grantees {
+		columns = append(columns, sectionColumn{grantee, grantMatrixColumnWidth})
+	}
+	var rows [][]string
+	for i, table := range tables {
+		row := []string{strconv.Itoa(i + 1), table["TABLE_NAME"]}
+		for _, grantee := range grantees {
+			row = append(row, tablePrivilegesOf(grants, grantee, table["TABLE_NAME"]))
+		}
+		rows = append(rows, row)
+	}
+	sectionRequests, nextRow := createSectionRequests(sheetId, 3, "テーブル権限一覧", columns, rows)
+	requests = append(requests, sectionRequests...)
+
+	var schemaRows [][]string
+	for _, group := range schemaGrantGroupsOf(grants) {
+		schemaRows = append(schemaRows, []string{
+			strconv.Itoa(len(schemaRows) + 1),
+			group.grantee,
+			group.level,
+			group.schema,
+			strings.Join(group.privileges, ", "),
+			mark(group.grantable),
+		})
+	}
+	if len(schemaRows) > 0 {
+		sectionRequests, _ = createSectionRequests(sheetId, nextRow+1, "グローバル・データベース権限一覧", schemaGrantSectionColumns, schemaRows)
+		requests = append(requests, sectionRequests...)
+	}
+
+	return requests
+}
+
+// granteesOf は権限を付与されたユーザーを記載順に重複なく返します。
+func granteesOf(grants []map[string]string) []string {
+	var grantees []string
+	seen := make(map[string]bool)
+	for _, grant := range grants {
+		if !seen[grant["GRANTEE"]] {
+			seen[grant["GRANTEE"]] = true
+			grantees = append(grantees, grant["GRANTEE"])
+		}
+	}
+	return grantees
+}
+
+// tablePrivilegesOf は grantee がテーブル tableName に対して持つ権限を「SELECT, INSERT, UPDATE(name, email)」の形式で返します。
+// グローバル・データベース単位の権限はすべてのテーブルに及ぶものとして含め、カラム単位の権限は権限の後ろにカラム名を付けます。
+func tablePrivilegesOf(grants []map[string]string, grantee, tableName string) string {
+	privileges := make(map[string]bool)
+	columnPrivileges := make(map[string][]string)
+	for _, grant := range grants {
+		if grant["GRANTEE"] != grantee {
+			continue
+		}
+		switch grant["PRIVILEGE_LEVEL"] {
+		case "GLOBAL", "SCHEMA":
+			privileges[grant["PRIVILEGE_TYPE"]] = true
+		case "TABLE":
+			if grant["TABLE_NAME"] == tableName {
+				privileges[grant["PRIVILEGE_TYPE"]] = true
+			}
+		case "COLUMN":
+			if grant["TABLE_NAME"] == tableName {
+				columnPrivileges[grant["PRIVILEGE_TYPE"]] = append(columnPrivileges[grant["PRIVILEGE_TYPE"]], grant["COLUMN_NAME"])
+			}
+		}
+	}
+
+	var result []string
+	for _, privilege := range tablePrivilegeOrder {
+		if privileges[privilege] {
+			result = append(result, privilege)
+		} else if columns := columnPrivileges[privilege]; len(columns) > 0 {
+			result = append(result, privilege+"("+strings.Join(columns, ", ")+")")
+		}
+	}
+	return strings.Join(result, ", ")
+}
+
+// schemaGrantGroup はユーザー・範囲・データベースごとにまとめたグローバル・データベース単位の権限です。
+type schemaGrantGroup struct {
+	grantee    string
+	level      string
+	schema     string
+	privileges []string
+	grantable  bool
+}
+
+// schemaGrantGroupsOf はグローバル・データベース単位の権限をユーザー・範囲・データベースごとにまとめます。
+func schemaGrantGroupsOf(grants []map[string]string) []*schemaGrantGroup {
+	var groups []*schemaGrantGroup
+	for _, grant := range grants {
+		if grant["PRIVILEGE_LEVEL"] != "GLOBAL" && grant["PRIVILEGE_LEVEL"] != "SCHEMA" {
+			continue
+		}
+		var group *schemaGrantGroup
+		if n := len(groups); n > 0 && groups[n-1].grantee == grant["GRANTEE"] &&
+			groups[n-1].level == grant["PRIVILEGE_LEVEL"] && groups[n-1].schema == grant["TABLE_SCHEMA"] {
+			group = groups[n-1]
+		} else {
+			group = &schemaGrantGroup{grantee: grant["GRANTEE"], level: grant["PRIVILEGE_LEVEL"], schema: grant["TABLE_SCHEMA"]}
+			groups = append(groups, group)
+		}
+		group.privileges = append(group.privileges, grant["PRIVILEGE_TYPE"])
+		group.grantable = group.grantable || grant["IS_GRANTABLE"] == "○"
+	}
+	return groups
+}
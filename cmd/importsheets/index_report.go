@@ -0,0 +1,110 @@
+package main
+
+import (
+	"export-db-info/internal/google_internal"
+	"export-db-info/internal/model/google_model"
+	"google.golang.org/api/sheets/v4"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// indexReportSheetName は削除を検討できるインデックスをまとめたシートの名前です。
+const indexReportSheetName = "インデックス整理"
+
+// indexReportSectionColumns は「削除候補インデックス一覧」セクションの列です。
+var indexReportSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"テーブル名", 3},
+	{"インデックス名", 3},
+	{"理由", 1},
+	{"カラム", 3},
+	{"包含するインデックス", 3},
+	{"読み取り回数", 1},
+}
+
+// indexReportReasons は削除を検討する理由のシートでの表示名です。
+var indexReportReasons = map[string]string{
+	"UNUSED":    "未使用",
+	"REDUNDANT": "冗長",
+}
+
+// createIndexReportSheet は meta/index_report.csv の内容から「インデックス整理」シートを作成します。
+// インデックスを調べていない場合・削除候補がない場合はシートを作成せずnilを返します。
+func createIndexReportSheet(sheSrv *sheets.Service, spreadsheetId string, csvDir string) *sheetEntry {
+	reports, err := readMetaCSVRows(csvDir, "index_report.csv")
+	if err != nil {
+		log.Printf("Unable to read index report csv: %v", err)
+	}
+	if len(reports) == 0 {
+		return nil
+	}
+
+	resp, err := sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{
+			AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: indexReportSheetName}},
+		}},
+	}).Do()
+	if err != nil {
+		log.Printf("Unable to create new sheet: %v", err)
+		return nil
+	}
+	if len(resp.Replies) == 0 || resp.Replies[0].AddSheet == nil {
+		log.Fatal("Failed to get the new sheet ID")
+	}
+	sheetId := resp.Replies[0].AddSheet.Properties.SheetId
+
+	_, err = sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: createIndexReportSheetRequests(sheetId, reports),
+	}).Do()
+	if err != nil {
+		log.Printf("Unable to create new sheet: %v", err)
+	}
+
+	return &sheetEntry{name: indexReportSheetName, id: sheetId}
+}
+
+// createIndexReportSheetRequests は「インデックス整理」シートのレイアウトを作成します。
+// 削除候補の一覧の後ろに、削除するSQLをまとめて並べます。
+func createIndexReportSheetRequests(sheetId int64, reports []map[string]string) []*sheets.Request {
+	var requests []*sheets.Request
+
+	requests = append(requests, google_internal.CreateSheetLayoutRequest(
+		sheetId,
+		&google_model.RangeOption{StartRow: 0, EndRow: 2, StartCol: 0, EndCol: 3},
+		true,
+		"CENTER",
+		"MIDDLE",
+		routineTitleColor,
+		&sheets.Color{Red: 1, Green: 1, Blue: 1},
+		"インデックス整理",
+		"",
+		&sheets.TextFormat{FontSize: 10, Bold: true},
+	)...)
+
+	var rows [][]string
+	var statements []string
+	for i, report := range reports {
+		dominant := report["DOMINANT_INDEX_NAME"]
+		if report["DOMINANT_INDEX_COLUMNS"] != "" {
+			dominant += "（" + report["DOMINANT_INDEX_COLUMNS"] + "）"
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			report["TABLE_NAME"],
+			report["INDEX_NAME"],
+			indexReportReasons[report["REASON"]],
+			report["INDEX_COLUMNS"],
+			dominant,
+			report["COUNT_READ"],
+		})
+		statements = append(statements, report["DROP_STATEMENT"]+";")
+	}
+	sectionRequests, nextRow := createSectionRequests(sheetId, 3, "削除候補インデックス一覧", indexReportSectionColumns, rows)
+	requests = append(requests, sectionRequests...)
+
+	definitionRequests, _ := createDefinitionBlockRequests(sheetId, nextRow+1, "削除するSQL", strings.Join(statements, "\n"), routineTitleColor)
+	requests = append(requests, definitionRequests...)
+
+	return requests
+}
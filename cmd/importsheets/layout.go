@@ -0,0 +1,601 @@
+package main
+
+import (
+	"export-db-info/internal/google_internal"
+	"export-db-info/internal/model/google_model"
+	"export-db-info/pkg/csv"
+	"google.golang.org/api/sheets/v4"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// property はシートに表示するラベルと値の組です。
+type property struct {
+	label string
+	value string
+}
+
+// propertiesPerRow はプロパティブロックの1行に並べるラベルと値の組の数です（1組あたり2列で14列分）。
+const propertiesPerRow = 7
+
+// readMetaCSV は meta ディレクトリのCSVを読み込み、指定したカラムの値をキーとするマップで返します。
+func readMetaCSV(csvDir, fileName, keyColumn string) (map[string]map[string]string, error) {
+	rows, err := readMetaCSVRows(csvDir, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]map[string]string, len(rows))
+	for _, row := range rows {
+		result[row[keyColumn]] = row
+	}
+	return result, nil
+}
+
+// readMetaCSVRows は meta ディレクトリのCSVを読み込み、記載順の行を返します。
+func readMetaCSVRows(csvDir, fileName string) ([]map[string]string, error) {
+	return csv.ReadFile(filepath.Join(csvDir, "meta", fileName))
+}
+
+// tablePropertiesOf はテーブル単位の情報からシートのプロパティブロックに表示する項目を作成します。
+func tablePropertiesOf(tableInfo map[string]string) []property {
+	if tableInfo == nil {
+		return nil
+	}
+	properties := []property{
+		{"エンジン", tableInfo["ENGINE"]},
+		{"照合順序", tableInfo["COLLATION"]},
+		{"行フォーマット", tableInfo["ROW_FORMAT"]},
+		{"AUTO_INCREMENT", tableInfo["AUTO_INCREMENT"]},
+		{"テーブル作成日時", tableInfo["CREATE_TIME"]},
+		{"テーブル更新日時", tableInfo["UPDATE_TIME"]},
+		{"推定行数", tableInfo["TABLE_ROWS"]},
+		{"データサイズ", formatBytes(tableInfo["DATA_LENGTH"])},
+		{"インデックスサイズ", formatBytes(tableInfo["INDEX_LENGTH"])},
+		{"未使用領域", formatBytes(tableInfo["DATA_FREE"])},
+	}
+	// システムバージョニングされたテーブル（MariaDB）のみ期間を表示する
+	if tableInfo["SYSTEM_VERSIONED"] == "○" {
+		properties = append(properties,
+			property{"システムバージョニング", tableInfo["SYSTEM_VERSIONED"]},
+			property{"期間", tableInfo["PERIODS"]},
+		)
+	}
+	return properties
+}
+
+// formatBytes はバイト数を「12.3 MB」のような単位付きの表記に変換します。数値でない場合はそのまま返します。
+func formatBytes(value string) string {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	i := 0
+	for n >= 1024 && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+	if i == 0 {
+		return strconv.FormatFloat(n, 'f', 0, 64) + " " + units[i]
+	}
+	return strconv.FormatFloat(n, 'f', 1, 64) + " " + units[i]
+}
+
+// createPropertyBlockRequests はラベル行と値行を交互に並べたプロパティブロックを作成し、ブロックの次の行番号を返します。
+func createPropertyBlockRequests(sheetId int64, startRow int64, properties []property) ([]*sheets.Request, int64) {
+	var requests []*sheets.Request
+
+	row := startRow
+	for i, p := range properties {
+		if i > 0 && i%propertiesPerRow == 0 {
+			row += 2
+		}
+		col := int64(i%propertiesPerRow) * 2
+
+		requests = append(requests, google_internal.CreateSheetLayoutRequest(
+			sheetId,
+			&google_model.RangeOption{StartRow: row, EndRow: row + 1, StartCol: col, EndCol: col + 2},
+			true,
+			"CENTER",
+			"MIDDLE",
+			&sheets.Color{Red: 0.25, Green: 0.25, Blue: 0.25},
+			&sheets.Color{Red: 1, Green: 1, Blue: 1},
+			p.label,
+			"",
+			&sheets.TextFormat{FontSize: 10, Bold: false},
+		)...)
+
+		requests = append(requests, google_internal.CreateSheetLayoutRequest(
+			sheetId,
+			&google_model.RangeOption{StartRow: row + 1, EndRow: row + 2, StartCol: col, EndCol: col + 2},
+			true,
+			"CENTER",
+			"MIDDLE",
+			&sheets.Color{Red: 1, Green: 1, Blue: 1},
+			&sheets.Color{Red: 0.25, Green: 0.25, Blue: 0.25},
+			p.value,
+			"",
+			&sheets.TextFormat{FontSize: 10, Bold: false},
+		)...)
+	}
+
+	return requests, row + 2
+}
+
+// sectionColumn は表形式のセクションの列の定義です。
+type sectionColumn struct {
+	header string
+	width  int64 // 結合する列数
+}
+
+// indexSectionColumns は「インデックス一覧」セクションの列です（カラム一覧と同じ14列分）。
+var indexSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"インデックス名", 3},
+	{"カラム", 4},
+	{"ユニーク", 1},
+	{"種類", 1},
+	{"可視", 1},
+	{"コメント", 3},
+}
+
+// foreignKeySectionColumns は「外部キー一覧」セクションの列です。
+var foreignKeySectionColumns = []sectionColumn{
+	{"No", 1},
+	{"制約名", 3},
+	{"カラム", 3},
+	{"参照先テーブル", 2},
+	{"参照先カラム", 3},
+	{"ON UPDATE", 1},
+	{"ON DELETE", 1},
+}
+
+// columnAttributeSectionColumns は「カラム属性一覧」セクションの列です。
+var columnAttributeSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"カラム名", 3},
+	{"付加情報", 2},
+	{"生成列", 3},
+	{"文字セット", 1},
+	{"照合順序", 2},
+	{"値の一覧", 2},
+}
+
+// profileSectionColumns は「値の傾向」セクションの列です。
+var profileSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"カラム名", 3},
+	{"NULLの割合", 1},
+	{"異なる値の数", 1},
+	{"最小値", 2},
+	{"最大値", 2},
+	{"平均長", 1},
+	{"出現回数の多い値", 5},
+}
+
+// checkSectionColumns は「CHECK制約一覧」セクションの列です。
+var checkSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"制約名", 3},
+	{"条件式", 9},
+	{"有効", 1},
+}
+
+// partitionSectionColumns は「パーティション一覧」セクションの列です。
+var partitionSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"パーティション名", 3},
+	{"値の範囲", 4},
+	{"サブパーティション", 3},
+	{"推定行数", 1},
+	{"コメント", 2},
+}
+
+// triggerSectionColumns は「トリガー一覧」セクションの列です。
+var triggerSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"トリガー名", 4},
+	{"タイミング", 2},
+	{"イベント", 3},
+	{"実行順", 1},
+	{"定義者", 3},
+}
+
+// readTableCSV は indexes などのサブディレクトリにあるテーブル単位のCSVを読み込みます。
+func readTableCSV(csvDir, subDir, tableName string) ([]map[string]string, error) {
+	return csv.ReadFile(filepath.Join(csvDir, subDir, tableName+".csv"))
+}
+
+// indexRowsOf はカラム単位のインデックス情報をインデックス単位の行にまとめます。
+// 複合インデックスのカラムは定義順に「,」区切りで、プレフィックス長・降順・部分インデックスの条件を添えて表示します。
+func indexRowsOf(indexColumns []map[string]string) [][]string {
+	var rows [][]string
+	var parts []string
+	var current map[string]string
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		columns := strings.Join(parts, ", ")
+		if current["CONDITION"] != "" {
+			columns += " WHERE " + current["CONDITION"]
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(len(rows) + 1),
+			current["INDEX_NAME"],
+			columns,
+			current["IS_UNIQUE"],
+			current["INDEX_TYPE"],
+			current["IS_VISIBLE"],
+			current["COMMENT"],
+		})
+	}
+
+	for _, col := range indexColumns {
+		if current == nil || current["INDEX_NAME"] != col["INDEX_NAME"] {
+			flush()
+			current = col
+			parts = nil
+		}
+
+		part := col["COLUMN_NAME"]
+		if part == "" {
+			part = "(" + col["EXPRESSION"] + ")"
+		}
+		if col["SUB_PART"] != "" {
+			part += "(" + col["SUB_PART"] + ")"
+		}
+		if col["SORT_ORDER"] == "DESC" {
+			part += " DESC"
+		}
+		parts = append(parts, part)
+	}
+	flush()
+
+	return rows
+}
+
+// foreignKeyRowsOf はカラム単位の外部キー情報を制約単位の行にまとめます。
+// SQLiteのように制約名を持たない場合があるため、ORDINAL_POSITION が1の行を制約の区切りとします。
+func foreignKeyRowsOf(foreignKeyColumns []map[string]string) [][]string {
+	var rows [][]string
+	var columns, refColumns []string
+	var current map[string]string
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(len(rows) + 1),
+			current["CONSTRAINT_NAME"],
+			strings.Join(columns, ", "),
+			current["REFERENCED_TABLE_NAME"],
+			strings.Join(refColumns, ", "),
+			current["UPDATE_RULE"],
+			current["DELETE_RULE"],
+		})
+	}
+
+	for _, col := range foreignKeyColumns {
+		if current == nil || col["ORDINAL_POSITION"] == "1" {
+			flush()
+			current = col
+			columns, refColumns = nil, nil
+		}
+		columns = append(columns, col["COLUMN_NAME"])
+		refColumns = append(refColumns, col["REFERENCED_COLUMN_NAME"])
+	}
+	flush()
+
+	return rows
+}
+
+// csvValue は見出し行から列の位置を求めて値を返します。列がない古い形式のCSVの場合は空文字列を返します。
+func csvValue(headers, record []string, header string) string {
+	for i, h := range headers {
+		if h == header && i < len(record) {
+			return record[i]
+		}
+	}
+	return ""
+}
+
+// defaultValueOf はカラム一覧のCSVの1行から「デフォルト値」列に表示する値を作成します。
+// 文字列の 'NULL' や空文字列と区別できるよう、数値以外のリテラルは引用符で囲み、デフォルト値がないNULL許容のカラムは NULL と表示します。
+func defaultValueOf(headers, record []string) string {
+	value := csvValue(headers, record, "COLUMN_DEFAULT")
+	switch csvValue(headers, record, "DEFAULT_KIND") {
+	case "LITERAL":
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return value
+		}
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case "EXPRESSION":
+		return value
+	default:
+		if csvValue(headers, record, "IS_NULLABLE") == "○" {
+			return "NULL"
+		}
+		return ""
+	}
+}
+
+// columnAttributeRowsOf はカラム一覧のCSV（先頭行は見出し）から「カラム属性一覧」セクションの行を作成します。
+// 付加情報・生成列・文字セット・照合順序・値の一覧のいずれかを持つカラムのみを対象とし、No はカラム一覧の No と合わせます。
+func columnAttributeRowsOf(records [][]string) [][]string {
+	if len(records) == 0 {
+		return nil
+	}
+	headers := records[0]
+	value := func(record []string, header string) string {
+		return csvValue(headers, record, header)
+	}
+
+	var rows [][]string
+	for ri, record := range records[1:] {
+		generated := value(record, "GENERATED")
+		// 生成列の種類は「生成列」に表示するため、MySQLの EXTRA に含まれる「VIRTUAL GENERATED」などは除く
+		extra := strings.TrimSpace(strings.Replace(value(record, "EXTRA"), generated+" GENERATED", "", 1))
+		var expression string
+		if generated != "" {
+			expression = "AS (" + value(record, "GENERATION_EXPRESSION") + ") " + generated
+		}
+		row := []string{
+			strconv.Itoa(ri + 1),
+			value(record, "COLUMN_NAME"),
+			extra,
+			expression,
+			value(record, "CHARACTER_SET_NAME"),
+			value(record, "COLLATION_NAME"),
+			value(record, "ENUM_VALUES"),
+		}
+		if strings.Join(row[2:], "") != "" {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// profileRowsOf はカラム定義のCSVの値の傾向の列から「値の傾向」セクションの行と見出しを作成します。
+// プロファイリングしていない場合は行を返しません。見出しにはサンプリングした行数を含めます。
+func profileRowsOf(records [][]string) ([][]string, string) {
+	if len(records) == 0 {
+		return nil, ""
+	}
+	headers := records[0]
+	value := func(record []string, header string) string {
+		return csvValue(headers, record, header)
+	}
+
+	var rows [][]string
+	var sampledRows string
+	partial := false
+	for ri, record := range records[1:] {
+		if value(record, "PROFILE_SAMPLED_ROWS") == "" {
+			continue
+		}
+		sampledRows = value(record, "PROFILE_SAMPLED_ROWS")
+		partial = partial || value(record, "PROFILE_PARTIAL") == "○"
+
+		nullRatio := value(record, "NULL_RATIO")
+		if ratio, err := strconv.ParseFloat(nullRatio, 64); err == nil {
+			nullRatio = strconv.FormatFloat(ratio*100, 'f', 1, 64) + "%"
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(ri + 1),
+			value(record, "COLUMN_NAME"),
+			nullRatio,
+			value(record, "DISTINCT_ESTIMATE"),
+			value(record, "MIN_VALUE"),
+			value(record, "MAX_VALUE"),
+			value(record, "AVG_LENGTH"),
+			value(record, "TOP_VALUES"),
+		})
+	}
+	if len(rows) == 0 {
+		return nil, ""
+	}
+
+	title := "値の傾向（サンプル " + sampledRows + " 行"
+	if partial {
+		title += "・時間の上限で打ち切り"
+	}
+	return rows, title + "）"
+}
+
+// checkRowsOf はCHECK制約の情報を「CHECK制約一覧」セクションの行に変換します。
+func checkRowsOf(checks []map[string]string) [][]string {
+	var rows [][]string
+	for i, check := range checks {
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			check["CONSTRAINT_NAME"],
+			check["CHECK_CLAUSE"],
+			check["ENFORCED"],
+		})
+	}
+	return rows
+}
+
+// partitionPropertiesOf はパーティション情報からシートのプロパティブロックに表示するパーティション方式を作成します。
+// パーティション方式はどの行にも同じ値が出力されているため先頭の行から取得します。
+func partitionPropertiesOf(partitions []map[string]string) []property {
+	if len(partitions) == 0 {
+		return nil
+	}
+	first := partitions[0]
+	return []property{
+		{"パーティション方式", first["PARTITION_METHOD"]},
+		{"パーティション式", first["PARTITION_EXPRESSION"]},
+		{"サブパーティション方式", first["SUBPARTITION_METHOD"]},
+		{"サブパーティション式", first["SUBPARTITION_EXPRESSION"]},
+	}
+}
+
+// partitionRowsOf はサブパーティション単位のパーティション情報をパーティション単位の行にまとめます。
+// サブパーティションは名前を「,」区切りで表示し、推定行数はサブパーティションの合計とします。
+func partitionRowsOf(partitions []map[string]string) [][]string {
+	var rows [][]string
+	var subpartitions []string
+	var tableRows int64
+	var hasRows bool
+	var current map[string]string
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		var estimate string
+		if hasRows {
+			estimate = strconv.FormatInt(tableRows, 10)
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(len(rows) + 1),
+			current["PARTITION_NAME"],
+			current["PARTITION_BOUND"],
+			strings.Join(subpartitions, ", "),
+			estimate,
+			current["PARTITION_COMMENT"],
+		})
+	}
+
+	for _, partition := range partitions {
+		if current == nil || current["PARTITION_NAME"] != partition["PARTITION_NAME"] {
+			flush()
+			current = partition
+			subpartitions, tableRows, hasRows = nil, 0, false
+		}
+		if partition["SUBPARTITION_NAME"] != "" {
+			subpartitions = append(subpartitions, partition["SUBPARTITION_NAME"])
+		}
+		if n, err := strconv.ParseInt(partition["TABLE_ROWS"], 10, 64); err == nil {
+			tableRows += n
+			hasRows = true
+		}
+	}
+	flush()
+
+	return rows
+}
+
+// triggerRowsOf はトリガー情報を「トリガー一覧」セクションの行に変換します。
+func triggerRowsOf(triggers []map[string]string) [][]string {
+	var rows [][]string
+	for i, trigger := range triggers {
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			trigger["TRIGGER_NAME"],
+			trigger["ACTION_TIMING"],
+			trigger["EVENT_MANIPULATION"],
+			trigger["ACTION_ORDER"],
+			trigger["DEFINER"],
+		})
+	}
+	return rows
+}
+
+// createDefinitionBlockRequests はSQLの定義（ビューのSELECT文・トリガーやルーチンの本体）を表示するブロックを作成し、ブロックの次の行番号を返します。
+// 長い定義でも読めるよう、行数に応じて複数行分を結合して表示します。
+func createDefinitionBlockRequests(sheetId int64, startRow int64, title, definition string, titleColor *sheets.Color) ([]*sheets.Request, int64) {
+	var requests []*sheets.Request
+
+	requests = append(requests, google_internal.CreateSheetLayoutRequest(
+		sheetId,
+		&google_model.RangeOption{StartRow: startRow, EndRow: startRow + 1, StartCol: 0, EndCol: 3},
+		true,
+		"CENTER",
+		"MIDDLE",
+		titleColor,
+		&sheets.Color{Red: 1, Green: 1, Blue: 1},
+		title,
+		"",
+		&sheets.TextFormat{FontSize: 10, Bold: true},
+	)...)
+
+	height := int64(strings.Count(definition, "\n") + 1)
+	if height < 5 {
+		height = 5
+	} else if height > 40 {
+		height = 40
+	}
+	requests = append(requests, google_internal.CreateSheetLayoutRequest(
+		sheetId,
+		&google_model.RangeOption{StartRow: startRow + 1, EndRow: startRow + 1 + height, StartCol: 0, EndCol: 14},
+		true,
+		"LEFT",
+		"TOP",
+		&sheets.Color{Red: 1, Green: 1, Blue: 1},
+		&sheets.Color{Red: 0.25, Green: 0.25, Blue: 0.25},
+		definition,
+		"",
+		&sheets.TextFormat{FontSize: 10, Bold: false},
+	)...)
+
+	return requests, startRow + 1 + height
+}
+
+// createSectionRequests はセクションの見出し・表の見出し行・値の行を作成し、セクションの次の行番号を返します。
+func createSectionRequests(sheetId int64, startRow int64, title string, columns []sectionColumn, rows [][]string) ([]*sheets.Request, int64) {
+	var requests []*sheets.Request
+
+	requests = append(requests, google_internal.CreateSheetLayoutRequest(
+		sheetId,
+		&google_model.RangeOption{StartRow: startRow, EndRow: startRow + 1, StartCol: 0, EndCol: 3},
+		true,
+		"CENTER",
+		"MIDDLE",
+		&sheets.Color{Red: 0.25, Green: 0.25, Blue: 0.25},
+		&sheets.Color{Red: 1, Green: 1, Blue: 1},
+		title,
+		"",
+		&sheets.TextFormat{FontSize: 10, Bold: true},
+	)...)
+
+	headerRow := startRow + 1
+	col := int64(0)
+	for _, c := range columns {
+		requests = append(requests, google_internal.CreateSheetLayoutRequest(
+			sheetId,
+			&google_model.RangeOption{StartRow: headerRow, EndRow: headerRow + 1, StartCol: col, EndCol: col + c.width},
+			true,
+			"CENTER",
+			"MIDDLE",
+			&sheets.Color{Red: 0.25, Green: 0.25, Blue: 0.25},
+			&sheets.Color{Red: 1, Green: 1, Blue: 1},
+			c.header,
+			"",
+			&sheets.TextFormat{FontSize: 10, Bold: false},
+		)...)
+		col += c.width
+	}
+
+	for ri, row := range rows {
+		r := headerRow + 1 + int64(ri)
+		col := int64(0)
+		for ci, c := range columns {
+			var value string
+			if ci < len(row) {
+				value = row[ci]
+			}
+			requests = append(requests, google_internal.CreateSheetLayoutRequest(
+				sheetId,
+				&google_model.RangeOption{StartRow: r, EndRow: r + 1, StartCol: col, EndCol: col + c.width},
+				true,
+				"CENTER",
+				"MIDDLE",
+				&sheets.Color{Red: 1, Green: 1, Blue: 1},
+				&sheets.Color{Red: 0.25, Green: 0.25, Blue: 0.25},
+				value,
+				"",
+				&sheets.TextFormat{FontSize: 10, Bold: false},
+			)...)
+			col += c.width
+		}
+	}
+
+	return requests, headerRow + 1 + int64(len(rows))
+}
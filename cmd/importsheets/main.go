@@ -13,6 +13,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -29,18 +30,41 @@ func main() {
 		log.Fatalf("Error initializing Google Sheets client: %v", err)
 	}
 
+	// Google Drive APIクライアントの初期化
+	drvSrv, err := google.InitializeDriveClient(ctx, serviceAccountKeyFile)
+	if err != nil {
+		log.Fatalf("Error initializing Google Drive client: %v", err)
+	}
+
 	// CSVファイルが保存されているディレクトリのパス
 	csvDir := os.Getenv("CSV_DIRECTORY")
+	lastPass := filepath.Base(csvDir)
+
+	// スキーマごとに出力したCSV（exportcsvが meta/schemas.csv に一覧を出力）は、スキーマごとにスプレッドシートを作成
+	schemas, err := readMetaCSVRows(csvDir, "schemas.csv")
+	if err != nil {
+		log.Fatalf("Unable to read schemas csv: %v", err)
+	}
+	if schemas == nil {
+		importSpreadsheet(sheSrv, drvSrv, csvDir, lastPass)
+		return
+	}
+	for _, schema := range schemas {
+		schemaName := schema["SCHEMA_NAME"]
+		importSpreadsheet(sheSrv, drvSrv, filepath.Join(csvDir, schemaName), lastPass+"_"+schemaName)
+	}
+}
+
+// importSpreadsheet は csvDir のCSVから title という名前のスプレッドシートを作成し、共有します。
+func importSpreadsheet(sheSrv *sheets.Service, drvSrv *drive.Service, csvDir, title string) {
 	files, err := ioutil.ReadDir(csvDir)
 	if err != nil {
 		log.Fatalf("Unable to read directory: %v", err)
 	}
 
-	lastPass := filepath.Base(csvDir)
-
 	spreadsheet := &sheets.Spreadsheet{
 		Properties: &sheets.SpreadsheetProperties{
-			Title: lastPass,
+			Title: title,
 		},
 		Sheets: []*sheets.Sheet{{
 			Properties: &sheets.SheetProperties{
@@ -62,11 +86,11 @@ func main() {
 		indexSheetId = createdSpreadsheet.Sheets[0].Properties.SheetId
 	}
 
-	// インデックスページに「テーブル名」というテキストを挿入
+	// インデックスページに「テーブル名」というテキストと、テーブルのサイズの列の見出しを挿入
 	var indexRequests []*sheets.Request
 	indexRequests = append(indexRequests, google_internal.CreateSheetLayoutRequest(
 		indexSheetId,
-		&google_model.RangeOption{StartRow: 0, EndRow: 2, StartCol: 0, EndCol: 5},
+		&google_model.RangeOption{StartRow: 0, EndRow: 1, StartCol: 0, EndCol: indexSizeStartCol + int64(len(indexSizeHeaders))},
 		true,
 		"CENTER",
 		"MIDDLE",
@@ -76,6 +100,7 @@ func main() {
 		"",
 		&sheets.TextFormat{FontSize: 10, Bold: true},
 	)...)
+	indexRequests = append(indexRequests, createIndexHeaderRequests(indexSheetId)...)
 
 	// バッチリクエストの実行
 	_, err = sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
@@ -92,15 +117,18 @@ func main() {
 		EmailAddress: os.Getenv("GOOGLE_SERVICE_ACCOUNT_EMAIL"), // 共有するユーザーのメールアドレス
 	}
 
-	// Google Drive APIクライアントの初期化
-	drvSrv, err := google.InitializeDriveClient(ctx, serviceAccountKeyFile)
-
 	_, err = drvSrv.Permissions.Create(spreadsheetId, permission).Do()
 	if err != nil {
 		log.Fatalf("Unable to create permission: %v", err)
 	}
 
-	log.Println("Spreadsheet shared successfully.")
+	log.Printf("Spreadsheet %s shared successfully.", title)
+
+	// テーブル単位の情報（exportcsvが meta/tables.csv に出力）
+	tableInfos, err := readMetaCSV(csvDir, "tables.csv", "TABLE_NAME")
+	if err != nil {
+		log.Fatalf("Unable to read tables csv: %v", err)
+	}
 
 	// シート名とIDのマッピングを格納する変数
 	sheetMappings := make(map[string]int64)
@@ -123,6 +151,7 @@ func main() {
 			}
 
 			tableName := strings.TrimSuffix(file.Name(), ".csv")
+			tableInfo := tableInfos[tableName]
 
 			newSheet := &sheets.SheetProperties{
 				Title: tableName,
@@ -385,21 +414,29 @@ func main() {
 					newSheetId,
 					&google_model.RangeOption{StartRow: 2, EndRow: 4, StartCol: 2, EndCol: 14},
 					true,
-					"CENTER",
+					"LEFT",
 					"MIDDLE",
 					&sheets.Color{Red: 1, Green: 1, Blue: 1},
 					nil,
-					"",
+					tableInfo["COMMENT"],
 					"",
 					&sheets.TextFormat{FontSize: 10, Bold: false},
 				)...,
 			)
 
+			// テーブル情報（エンジン・照合順序など）のブロック
+			columnRow := int64(6)
+			if properties := tablePropertiesOf(tableInfo); len(properties) > 0 {
+				propertyRequests, nextRow := createPropertyBlockRequests(newSheetId, 5, properties)
+				requests = append(requests, propertyRequests...)
+				columnRow = nextRow + 1
+			}
+
 			requests = append(
 				requests,
 				google_internal.CreateSheetLayoutRequest(
 					newSheetId,
-					&google_model.RangeOption{StartRow: 6, EndRow: 7, StartCol: 0, EndCol: 1},
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 0, EndCol: 1},
 					true,
 					"CENTER",
 					"MIDDLE",
@@ -415,7 +452,7 @@ func main() {
 				requests,
 				google_internal.CreateSheetLayoutRequest(
 					newSheetId,
-					&google_model.RangeOption{StartRow: 6, EndRow: 7, StartCol: 1, EndCol: 4},
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 1, EndCol: 4},
 					true,
 					"CENTER",
 					"MIDDLE",
@@ -431,7 +468,7 @@ func main() {
 				requests,
 				google_internal.CreateSheetLayoutRequest(
 					newSheetId,
-					&google_model.RangeOption{StartRow: 6, EndRow: 7, StartCol: 4, EndCol: 5},
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 4, EndCol: 5},
 					true,
 					"CENTER",
 					"MIDDLE",
@@ -447,7 +484,7 @@ func main() {
 				requests,
 				google_internal.CreateSheetLayoutRequest(
 					newSheetId,
-					&google_model.RangeOption{StartRow: 6, EndRow: 7, StartCol: 5, EndCol: 6},
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 5, EndCol: 6},
 					true,
 					"CENTER",
 					"MIDDLE",
@@ -463,7 +500,7 @@ func main() {
 				requests,
 				google_internal.CreateSheetLayoutRequest(
 					newSheetId,
-					&google_model.RangeOption{StartRow: 6, EndRow: 7, StartCol: 6, EndCol: 7},
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 6, EndCol: 7},
 					true,
 					"CENTER",
 					"MIDDLE",
@@ -479,7 +516,7 @@ func main() {
 				requests,
 				google_internal.CreateSheetLayoutRequest(
 					newSheetId,
-					&google_model.RangeOption{StartRow: 6, EndRow: 7, StartCol: 7, EndCol: 8},
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 7, EndCol: 8},
 					true,
 					"CENTER",
 					"MIDDLE",
@@ -495,7 +532,7 @@ func main() {
 				requests,
 				google_internal.CreateSheetLayoutRequest(
 					newSheetId,
-					&google_model.RangeOption{StartRow: 6, EndRow: 7, StartCol: 8, EndCol: 9},
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 8, EndCol: 9},
 					true,
 					"CENTER",
 					"MIDDLE",
@@ -511,7 +548,7 @@ func main() {
 				requests,
 				google_internal.CreateSheetLayoutRequest(
 					newSheetId,
-					&google_model.RangeOption{StartRow: 6, EndRow: 7, StartCol: 9, EndCol: 10},
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 9, EndCol: 10},
 					true,
 					"CENTER",
 					"MIDDLE",
@@ -527,7 +564,7 @@ func main() {
 				requests,
 				google_internal.CreateSheetLayoutRequest(
 					newSheetId,
-					&google_model.RangeOption{StartRow: 6, EndRow: 7, StartCol: 10, EndCol: 11},
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 10, EndCol: 11},
 					true,
 					"CENTER",
 					"MIDDLE",
@@ -543,7 +580,7 @@ func main() {
 				requests,
 				google_internal.CreateSheetLayoutRequest(
 					newSheetId,
-					&google_model.RangeOption{StartRow: 6, EndRow: 7, StartCol: 11, EndCol: 12},
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 11, EndCol: 12},
 					true,
 					"CENTER",
 					"MIDDLE",
@@ -559,7 +596,23 @@ func main() {
 				requests,
 				google_internal.CreateSheetLayoutRequest(
 					newSheetId,
-					&google_model.RangeOption{StartRow: 6, EndRow: 7, StartCol: 12, EndCol: 14},
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 12, EndCol: 13},
+					true,
+					"CENTER",
+					"MIDDLE",
+					&sheets.Color{Red: 0.25, Green: 0.25, Blue: 0.25},
+					&sheets.Color{Red: 1, Green: 1, Blue: 1},
+					"デフォルト値",
+					"",
+					&sheets.TextFormat{FontSize: 10, Bold: false},
+				)...,
+			)
+
+			requests = append(
+				requests,
+				google_internal.CreateSheetLayoutRequest(
+					newSheetId,
+					&google_model.RangeOption{StartRow: columnRow, EndRow: columnRow + 1, StartCol: 13, EndCol: 14},
 					true,
 					"CENTER",
 					"MIDDLE",
@@ -581,7 +634,7 @@ func main() {
 					requests,
 					google_internal.CreateSheetLayoutRequest(
 						newSheetId,
-						&google_model.RangeOption{StartRow: int64(ri) + 6, EndRow: int64(ri) + 7, StartCol: 0, EndCol: 1},
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 0, EndCol: 1},
 						true,
 						"CENTER",
 						"MIDDLE",
@@ -597,7 +650,7 @@ func main() {
 					requests,
 					google_internal.CreateSheetLayoutRequest(
 						newSheetId,
-						&google_model.RangeOption{StartRow: int64(ri) + 6, EndRow: int64(ri) + 7, StartCol: 1, EndCol: 4},
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 1, EndCol: 4},
 						true,
 						"CENTER",
 						"MIDDLE",
@@ -613,7 +666,7 @@ func main() {
 					requests,
 					google_internal.CreateSheetLayoutRequest(
 						newSheetId,
-						&google_model.RangeOption{StartRow: int64(ri) + 6, EndRow: int64(ri) + 7, StartCol: 4, EndCol: 5},
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 4, EndCol: 5},
 						true,
 						"CENTER",
 						"MIDDLE",
@@ -629,7 +682,7 @@ func main() {
 					requests,
 					google_internal.CreateSheetLayoutRequest(
 						newSheetId,
-						&google_model.RangeOption{StartRow: int64(ri) + 6, EndRow: int64(ri) + 7, StartCol: 5, EndCol: 6},
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 5, EndCol: 6},
 						true,
 						"CENTER",
 						"MIDDLE",
@@ -645,7 +698,7 @@ func main() {
 					requests,
 					google_internal.CreateSheetLayoutRequest(
 						newSheetId,
-						&google_model.RangeOption{StartRow: int64(ri) + 6, EndRow: int64(ri) + 7, StartCol: 6, EndCol: 7},
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 6, EndCol: 7},
 						true,
 						"CENTER",
 						"MIDDLE",
@@ -661,7 +714,7 @@ func main() {
 					requests,
 					google_internal.CreateSheetLayoutRequest(
 						newSheetId,
-						&google_model.RangeOption{StartRow: int64(ri) + 6, EndRow: int64(ri) + 7, StartCol: 7, EndCol: 8},
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 7, EndCol: 8},
 						true,
 						"CENTER",
 						"MIDDLE",
@@ -677,7 +730,7 @@ func main() {
 					requests,
 					google_internal.CreateSheetLayoutRequest(
 						newSheetId,
-						&google_model.RangeOption{StartRow: int64(ri) + 6, EndRow: int64(ri) + 7, StartCol: 8, EndCol: 9},
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 8, EndCol: 9},
 						true,
 						"CENTER",
 						"MIDDLE",
@@ -693,7 +746,7 @@ func main() {
 					requests,
 					google_internal.CreateSheetLayoutRequest(
 						newSheetId,
-						&google_model.RangeOption{StartRow: int64(ri) + 6, EndRow: int64(ri) + 7, StartCol: 9, EndCol: 10},
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 9, EndCol: 10},
 						true,
 						"CENTER",
 						"MIDDLE",
@@ -709,7 +762,7 @@ func main() {
 					requests,
 					google_internal.CreateSheetLayoutRequest(
 						newSheetId,
-						&google_model.RangeOption{StartRow: int64(ri) + 6, EndRow: int64(ri) + 7, StartCol: 10, EndCol: 11},
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 10, EndCol: 11},
 						true,
 						"CENTER",
 						"MIDDLE",
@@ -725,7 +778,7 @@ func main() {
 					requests,
 					google_internal.CreateSheetLayoutRequest(
 						newSheetId,
-						&google_model.RangeOption{StartRow: int64(ri) + 6, EndRow: int64(ri) + 7, StartCol: 11, EndCol: 12},
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 11, EndCol: 12},
 						true,
 						"CENTER",
 						"MIDDLE",
@@ -741,7 +794,23 @@ func main() {
 					requests,
 					google_internal.CreateSheetLayoutRequest(
 						newSheetId,
-						&google_model.RangeOption{StartRow: int64(ri) + 6, EndRow: int64(ri) + 7, StartCol: 12, EndCol: 14},
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 12, EndCol: 13},
+						true,
+						"CENTER",
+						"MIDDLE",
+						&sheets.Color{Red: 1, Green: 1, Blue: 1},
+						&sheets.Color{Red: 0.25, Green: 0.25, Blue: 0.25},
+						defaultValueOf(records[0], record),
+						"",
+						&sheets.TextFormat{FontSize: 10, Bold: false},
+					)...,
+				)
+
+				requests = append(
+					requests,
+					google_internal.CreateSheetLayoutRequest(
+						newSheetId,
+						&google_model.RangeOption{StartRow: columnRow + int64(ri), EndRow: columnRow + int64(ri) + 1, StartCol: 13, EndCol: 14},
 						true,
 						"CENTER",
 						"MIDDLE",
@@ -754,6 +823,84 @@ func main() {
 				)
 			}
 
+			// 自動採番・生成列・文字セットなどの属性を持つカラムの一覧
+			sectionRow := columnRow + int64(len(records)) + 1
+			if attributeRows := columnAttributeRowsOf(records); len(attributeRows) > 0 {
+				sectionRequests, nextRow := createSectionRequests(newSheetId, sectionRow, "カラム属性一覧", columnAttributeSectionColumns, attributeRows)
+				requests = append(requests, sectionRequests...)
+				sectionRow = nextRow + 1
+			}
+
+			// サンプリングしたデータによるカラムの値の傾向（DB_PROFILE を指定した場合のみ）
+			if profileRows, title := profileRowsOf(records); len(profileRows) > 0 {
+				sectionRequests, nextRow := createSectionRequests(newSheetId, sectionRow, title, profileSectionColumns, profileRows)
+				requests = append(requests, sectionRequests...)
+				sectionRow = nextRow + 1
+			}
+
+			// インデックス一覧（exportcsvが indexes/<テーブル名>.csv に出力）
+			indexColumns, err := readTableCSV(csvDir, "indexes", tableName)
+			if err != nil {
+				log.Printf("Unable to read indexes csv: %v", err)
+			}
+			if indexRows := indexRowsOf(indexColumns); len(indexRows) > 0 {
+				sectionRequests, nextRow := createSectionRequests(newSheetId, sectionRow, "インデックス一覧", indexSectionColumns, indexRows)
+				requests = append(requests, sectionRequests...)
+				sectionRow = nextRow + 1
+			}
+
+			// 外部キー一覧（exportcsvが foreign_keys/<テーブル名>.csv に出力）
+			foreignKeyColumns, err := readTableCSV(csvDir, "foreign_keys", tableName)
+			if err != nil {
+				log.Printf("Unable to read foreign keys csv: %v", err)
+			}
+			if foreignKeyRows := foreignKeyRowsOf(foreignKeyColumns); len(foreignKeyRows) > 0 {
+				sectionRequests, nextRow := createSectionRequests(newSheetId, sectionRow, "外部キー一覧", foreignKeySectionColumns, foreignKeyRows)
+				requests = append(requests, sectionRequests...)
+				sectionRow = nextRow + 1
+			}
+
+			// CHECK制約一覧（exportcsvが checks/<テーブル名>.csv に出力）
+			checks, err := readTableCSV(csvDir, "checks", tableName)
+			if err != nil {
+				log.Printf("Unable to read checks csv: %v", err)
+			}
+			if len(checks) > 0 {
+				sectionRequests, nextRow := createSectionRequests(newSheetId, sectionRow, "CHECK制約一覧", checkSectionColumns, checkRowsOf(checks))
+				requests = append(requests, sectionRequests...)
+				sectionRow = nextRow + 1
+			}
+
+			// パーティション方式とパーティション一覧（exportcsvが partitions/<テーブル名>.csv に出力）
+			partitions, err := readTableCSV(csvDir, "partitions", tableName)
+			if err != nil {
+				log.Printf("Unable to read partitions csv: %v", err)
+			}
+			if len(partitions) > 0 {
+				propertyRequests, nextRow := createPropertyBlockRequests(newSheetId, sectionRow, partitionPropertiesOf(partitions))
+				requests = append(requests, propertyRequests...)
+				sectionRequests, nextRow := createSectionRequests(newSheetId, nextRow+1, "パーティション一覧", partitionSectionColumns, partitionRowsOf(partitions))
+				requests = append(requests, sectionRequests...)
+				sectionRow = nextRow + 1
+			}
+
+			// トリガー一覧と各トリガーの本体（exportcsvが triggers/<テーブル名>.csv に出力）
+			triggers, err := readTableCSV(csvDir, "triggers", tableName)
+			if err != nil {
+				log.Printf("Unable to read triggers csv: %v", err)
+			}
+			if len(triggers) > 0 {
+				sectionRequests, nextRow := createSectionRequests(newSheetId, sectionRow, "トリガー一覧", triggerSectionColumns, triggerRowsOf(triggers))
+				requests = append(requests, sectionRequests...)
+				sectionRow = nextRow + 1
+				for _, trigger := range triggers {
+					definitionRequests, nextRow := createDefinitionBlockRequests(newSheetId, sectionRow, trigger["TRIGGER_NAME"],
+						trigger["ACTION_STATEMENT"], &sheets.Color{Red: 0.25, Green: 0.25, Blue: 0.25})
+					requests = append(requests, definitionRequests...)
+					sectionRow = nextRow + 1
+				}
+			}
+
 			batchUpdateRequestForLayout := &sheets.BatchUpdateSpreadsheetRequest{
 				Requests: requests,
 			}
@@ -769,13 +916,86 @@ func main() {
 		}
 	}
 
+	// ビューのシート（exportcsvが meta/views.csv と views/<ビュー名>.csv に出力）
+	viewSheets := createViewSheets(sheSrv, spreadsheetId, csvDir)
+
+	// ルーチン・イベントのシート（exportcsvが meta/routines.csv と meta/events.csv に出力）
+	var otherSheets []sheetEntry
+	if routineSheet := createRoutineSheet(sheSrv, spreadsheetId, csvDir); routineSheet != nil {
+		otherSheets = append(otherSheets, *routineSheet)
+	}
+
+	// シーケンスのシート（exportcsvが meta/sequences.csv に出力）
+	if sequenceSheet := createSequenceSheet(sheSrv, spreadsheetId, csvDir); sequenceSheet != nil {
+		otherSheets = append(otherSheets, *sequenceSheet)
+	}
+
+	// 権限のシート（exportcsvが meta/grants.csv に出力）
+	if grantSheet := createGrantSheet(sheSrv, spreadsheetId, csvDir); grantSheet != nil {
+		otherSheets = append(otherSheets, *grantSheet)
+	}
+
+	// 削除を検討できるインデックスのシート（exportcsvが meta/index_report.csv に出力）
+	if indexReportSheet := createIndexReportSheet(sheSrv, spreadsheetId, csvDir); indexReportSheet != nil {
+		otherSheets = append(otherSheets, *indexReportSheet)
+	}
+
 	// インデックスページにシート名とリンクを追加するリクエストを作成
 	var updateIndexRequests []*sheets.Request
 	rowIndex := 0
-	for sheetName, sheetId := range sheetMappings {
-		updateIndexRequests = append(updateIndexRequests, createIndexEntryRequest(sheetName, sheetId, rowIndex, indexSheetId)...)
+	tableNames := make([]string, 0, len(sheetMappings))
+	for sheetName := range sheetMappings {
+		tableNames = append(tableNames, sheetName)
+	}
+	sort.Strings(tableNames)
+	for _, sheetName := range tableNames {
+		updateIndexRequests = append(updateIndexRequests, createIndexEntryRequest(sheetName, sheetMappings[sheetName], rowIndex, indexSheetId)...)
+		updateIndexRequests = append(updateIndexRequests, createIndexSizeRequests(tableInfos[sheetName], rowIndex, indexSheetId)...)
 		rowIndex++
 	}
+	// テーブルの目次にフィルタを設定し、サイズの列で並べ替えられるようにする
+	if len(tableNames) > 0 {
+		updateIndexRequests = append(updateIndexRequests, &sheets.Request{
+			SetBasicFilter: &sheets.SetBasicFilterRequest{Filter: &sheets.BasicFilter{Range: &sheets.GridRange{
+				SheetId:          indexSheetId,
+				StartRowIndex:    1,
+				EndRowIndex:      int64(len(tableNames) + 2),
+				StartColumnIndex: 0,
+				EndColumnIndex:   indexSizeStartCol + int64(len(indexSizeHeaders)),
+			}}},
+		})
+	}
+
+	// ビュー・その他のシートはテーブルの目次の下に別の見出しを付けて並べる
+	for _, group := range []struct {
+		title   string
+		color   *sheets.Color
+		entries []sheetEntry
+	}{
+		{"シート目次（ビュー名）", viewTitleColor, viewSheets},
+		{"シート目次（その他）", routineTitleColor, otherSheets},
+	} {
+		if len(group.entries) == 0 {
+			continue
+		}
+		titleRow := int64(rowIndex + 3)
+		updateIndexRequests = append(updateIndexRequests, google_internal.CreateSheetLayoutRequest(
+			indexSheetId,
+			&google_model.RangeOption{StartRow: titleRow, EndRow: titleRow + 2, StartCol: 0, EndCol: 5},
+			true,
+			"CENTER",
+			"MIDDLE",
+			group.color,
+			&sheets.Color{Red: 1, Green: 1, Blue: 1},
+			group.title,
+			"",
+			&sheets.TextFormat{FontSize: 10, Bold: true},
+		)...)
+		for i, entry := range group.entries {
+			updateIndexRequests = append(updateIndexRequests, createIndexEntryRequest(entry.name, entry.id, rowIndex+3+i, indexSheetId)...)
+		}
+		rowIndex += 3 + len(group.entries)
+	}
 
 	// インデックスページの更新を実行
 	_, err = sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
@@ -806,3 +1026,89 @@ func createIndexEntryRequest(sheetName string, sheetId int64, rowIndex int, inde
 
 	return sheetNameRequest
 }
+
+// indexSizeStartCol はインデックスページのテーブルのサイズの列の開始位置です（テーブル名の列の右隣）。
+const indexSizeStartCol = 5
+
+// indexSizeHeaders はインデックスページに表示するテーブルのサイズの列の見出しです。
+var indexSizeHeaders = []string{"推定行数", "データ(byte)", "インデックス(byte)", "合計(byte)"}
+
+// createIndexHeaderRequests は、インデックスページのテーブルの目次の見出し行を作成します。
+func createIndexHeaderRequests(indexSheetId int64) []*sheets.Request {
+	requests := google_internal.CreateSheetLayoutRequest(
+		indexSheetId,
+		&google_model.RangeOption{StartRow: 1, EndRow: 2, StartCol: 0, EndCol: indexSizeStartCol},
+		true,
+		"CENTER",
+		"MIDDLE",
+		&sheets.Color{Red: 0.25, Green: 0.25, Blue: 0.25},
+		&sheets.Color{Red: 1, Green: 1, Blue: 1},
+		"テーブル名",
+		"",
+		&sheets.TextFormat{FontSize: 10, Bold: false},
+	)
+	for i, header := range indexSizeHeaders {
+		col := indexSizeStartCol + int64(i)
+		requests = append(requests, google_internal.CreateSheetLayoutRequest(
+			indexSheetId,
+			&google_model.RangeOption{StartRow: 1, EndRow: 2, StartCol: col, EndCol: col + 1},
+			false,
+			"CENTER",
+			"MIDDLE",
+			&sheets.Color{Red: 0.25, Green: 0.25, Blue: 0.25},
+			&sheets.Color{Red: 1, Green: 1, Blue: 1},
+			header,
+			"",
+			&sheets.TextFormat{FontSize: 10, Bold: false},
+		)...)
+	}
+	return requests
+}
+
+// createIndexSizeRequests は、インデックスページのテーブルの行に推定行数とサイズを数値として書き込むリクエストを作成します。
+// 合計はデータとインデックスのサイズの和です。値がない場合は空欄とします。
+func createIndexSizeRequests(tableInfo map[string]string, rowIndex int, indexSheetId int64) []*sheets.Request {
+	tableRows := parseNumber(tableInfo["TABLE_ROWS"])
+	dataLength := parseNumber(tableInfo["DATA_LENGTH"])
+	indexLength := parseNumber(tableInfo["INDEX_LENGTH"])
+	var total *float64
+	if dataLength != nil && indexLength != nil {
+		sum := *dataLength + *indexLength
+		total = &sum
+	}
+
+	var cells []*sheets.CellData
+	for _, value := range []*float64{tableRows, dataLength, indexLength, total} {
+		cell := &sheets.CellData{
+			UserEnteredFormat: &sheets.CellFormat{
+				HorizontalAlignment: "RIGHT",
+				NumberFormat:        &sheets.NumberFormat{Type: "NUMBER", Pattern: "#,##0"},
+			},
+		}
+		if value != nil {
+			cell.UserEnteredValue = &sheets.ExtendedValue{NumberValue: value}
+		}
+		cells = append(cells, cell)
+	}
+
+	return []*sheets.Request{{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Start: &sheets.GridCoordinate{
+				SheetId:     indexSheetId,
+				RowIndex:    int64(rowIndex + 2),
+				ColumnIndex: indexSizeStartCol,
+			},
+			Rows:   []*sheets.RowData{{Values: cells}},
+			Fields: "userEnteredValue,userEnteredFormat(horizontalAlignment,numberFormat)",
+		},
+	}}
+}
+
+// parseNumber は数値の文字列を変換します。空欄など数値でない場合はnilを返します。
+func parseNumber(value string) *float64 {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"export-db-info/internal/google_internal"
+	"export-db-info/internal/model/google_model"
+	"google.golang.org/api/sheets/v4"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// routineSheetName はストアドプロシージャ・ストアドファンクションとイベントをまとめたシートの名前です。
+const routineSheetName = "ルーチン"
+
+// routineSectionColumns は「ルーチン一覧」セクションの列です。
+var routineSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"ルーチン名", 3},
+	{"種類", 1},
+	{"引数", 4},
+	{"戻り値", 2},
+	{"データアクセス", 2},
+	{"セキュリティ", 1},
+}
+
+// eventSectionColumns は「イベント一覧」セクションの列です。
+var eventSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"イベント名", 3},
+	{"スケジュール", 5},
+	{"状態", 2},
+	{"完了後保持", 1},
+	{"コメント", 2},
+}
+
+// routineTitleColor はルーチンのシートの見出しの背景色です。
+var routineTitleColor = &sheets.Color{Red: 0.35, Green: 0.2, Blue: 0.45}
+
+// createRoutineSheet は meta/routines.csv と meta/events.csv の内容から「ルーチン」シートを作成します。
+// ルーチンもイベントもない場合はシートを作成せずnilを返します。
+func createRoutineSheet(sheSrv *sheets.Service, spreadsheetId string, csvDir string) *sheetEntry {
+	routines, err := readMetaCSVRows(csvDir, "routines.csv")
+	if err != nil {
+		log.Printf("Unable to read routines csv: %v", err)
+	}
+	events, err := readMetaCSVRows(csvDir, "events.csv")
+	if err != nil {
+		log.Printf("Unable to read events csv: %v", err)
+	}
+	if len(routines) == 0 && len(events) == 0 {
+		return nil
+	}
+
+	resp, err := sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{
+			AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: routineSheetName}},
+		}},
+	}).Do()
+	if err != nil {
+		log.Printf("Unable to create new sheet: %v", err)
+		return nil
+	}
+	if len(resp.Replies) == 0 || resp.Replies[0].AddSheet == nil {
+		log.Fatal("Failed to get the new sheet ID")
+	}
+	sheetId := resp.Replies[0].AddSheet.Properties.SheetId
+
+	_, err = sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: createRoutineSheetRequests(sheetId, routines, events),
+	}).Do()
+	if err != nil {
+		log.Printf("Unable to create new sheet: %v", err)
+	}
+
+	return &sheetEntry{name: routineSheetName, id: sheetId}
+}
+
+// createRoutineSheetRequests は「ルーチン」シートのレイアウトを作成します。
+// ルーチン一覧・イベント一覧の後ろに、それぞれの本体を定義順に並べます。
+func createRoutineSheetRequests(sheetId int64, routines, events []map[string]string) []*sheets.Request {
+	var requests []*sheets.Request
+
+	requests = append(requests, google_internal.CreateSheetLayoutRequest(
+		sheetId,
+		&google_model.RangeOption{StartRow: 0, EndRow: 2, StartCol: 0, EndCol: 3},
+		true,
+		"CENTER",
+		"MIDDLE",
+		routineTitleColor,
+		&sheets.Color{Red: 1, Green: 1, Blue: 1},
+		"ルーチン仕様書",
+		"",
+		&sheets.TextFormat{FontSize: 10, Bold: true},
+	)...)
+
+	row := int64(3)
+	if len(routines) > 0 {
+		var rows [][]string
+		for i, routine := range routines {
+			rows = append(rows, []string{
+				strconv.Itoa(i + 1),
+				routine["ROUTINE_NAME"],
+				routine["ROUTINE_TYPE"],
+				routine["PARAMETERS"],
+				routine["RETURN_TYPE"],
+				routine["SQL_DATA_ACCESS"],
+				routine["SECURITY_TYPE"],
+			})
+		}
+		sectionRequests, nextRow := createSectionRequests(sheetId, row, "ルーチン一覧", routineSectionColumns, rows)
+		requests = append(requests, sectionRequests...)
+		row = nextRow + 1
+	}
+
+	if len(events) > 0 {
+		var rows [][]string
+		for i, event := range events {
+			rows = append(rows, []string{
+				strconv.Itoa(i + 1),
+				event["EVENT_NAME"],
+				eventScheduleOf(event),
+				event["STATUS"],
+				mark(event["ON_COMPLETION"] == "PRESERVE"),
+				event["COMMENT"],
+			})
+		}
+		sectionRequests, nextRow := createSectionRequests(sheetId, row, "イベント一覧", eventSectionColumns, rows)
+		requests = append(requests, sectionRequests...)
+		row = nextRow + 1
+	}
+
+	for _, routine := range routines {
+		title := routine["ROUTINE_NAME"] + "（" + routine["ROUTINE_TYPE"] + "）"
+		definitionRequests, nextRow := createDefinitionBlockRequests(sheetId, row, title, routine["ROUTINE_DEFINITION"], routineTitleColor)
+		requests = append(requests, definitionRequests...)
+		row = nextRow + 1
+	}
+	for _, event := range events {
+		title := event["EVENT_NAME"] + "（EVENT）"
+		definitionRequests, nextRow := createDefinitionBlockRequests(sheetId, row, title, event["EVENT_DEFINITION"], routineTitleColor)
+		requests = append(requests, definitionRequests...)
+		row = nextRow + 1
+	}
+
+	return requests
+}
+
+// eventScheduleOf はイベントの実行スケジュールを CREATE EVENT の ON SCHEDULE 句に近い表記で返します。
+func eventScheduleOf(event map[string]string) string {
+	if event["EVENT_TYPE"] == "ONE TIME" {
+		return "AT " + event["EXECUTE_AT"]
+	}
+
+	schedule := []string{"EVERY", event["INTERVAL_VALUE"], event["INTERVAL_FIELD"]}
+	if event["STARTS"] != "" {
+		schedule = append(schedule, "STARTS", event["STARTS"])
+	}
+	if event["ENDS"] != "" {
+		schedule = append(schedule, "ENDS", event["ENDS"])
+	}
+	return strings.Join(schedule, " ")
+}
+
+// mark は真偽値をシートに表示する「○」「×」に変換します。
+func mark(b bool) string {
+	if b {
+		return "○"
+	}
+	return "×"
+}
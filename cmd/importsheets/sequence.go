@@ -0,0 +1,97 @@
+package main
+
+import (
+	"export-db-info/internal/google_internal"
+	"export-db-info/internal/model/google_model"
+	"google.golang.org/api/sheets/v4"
+	"log"
+	"strconv"
+)
+
+// sequenceSheetName はシーケンスの一覧のシートの名前です。
+const sequenceSheetName = "シーケンス"
+
+// sequenceSectionColumns は「シーケンス一覧」セクションの列です。
+var sequenceSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"シーケンス名", 3},
+	{"データ型", 1},
+	{"開始値", 1},
+	{"増分", 1},
+	{"最小値", 2},
+	{"最大値", 2},
+	{"循環", 1},
+	{"所有カラム", 2},
+}
+
+// createSequenceSheet は meta/sequences.csv の内容から「シーケンス」シートを作成します。
+// シーケンスがない場合はシートを作成せずnilを返します。
+func createSequenceSheet(sheSrv *sheets.Service, spreadsheetId string, csvDir string) *sheetEntry {
+	sequences, err := readMetaCSVRows(csvDir, "sequences.csv")
+	if err != nil {
+		log.Printf("Unable to read sequences csv: %v", err)
+	}
+	if len(sequences) == 0 {
+		return nil
+	}
+
+	resp, err := sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{
+			AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: sequenceSheetName}},
+		}},
+	}).Do()
+	if err != nil {
+		log.Printf("Unable to create new sheet: %v", err)
+		return nil
+	}
+	if len(resp.Replies) == 0 || resp.Replies[0].AddSheet == nil {
+		log.Fatal("Failed to get the new sheet ID")
+	}
+	sheetId := resp.Replies[0].AddSheet.Properties.SheetId
+
+	_, err = sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: createSequenceSheetRequests(sheetId, sequences),
+	}).Do()
+	if err != nil {
+		log.Printf("Unable to create new sheet: %v", err)
+	}
+
+	return &sheetEntry{name: sequenceSheetName, id: sheetId}
+}
+
+// createSequenceSheetRequests は「シーケンス」シートのレイアウトを作成します。
+func createSequenceSheetRequests(sheetId int64, sequences []map[string]string) []*sheets.Request {
+	var requests []*sheets.Request
+
+	requests = append(requests, google_internal.CreateSheetLayoutRequest(
+		sheetId,
+		&google_model.RangeOption{StartRow: 0, EndRow: 2, StartCol: 0, EndCol: 3},
+		true,
+		"CENTER",
+		"MIDDLE",
+		routineTitleColor,
+		&sheets.Color{Red: 1, Green: 1, Blue: 1},
+		"シーケンス仕様書",
+		"",
+		&sheets.TextFormat{FontSize: 10, Bold: true},
+	)...)
+
+	var rows [][]string
+	for i, sequence := range sequences {
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			sequence["SEQUENCE_NAME"],
+			sequence["DATA_TYPE"],
+			sequence["START_VALUE"],
+			sequence["INCREMENT"],
+			sequence["MIN_VALUE"],
+			sequence["MAX_VALUE"],
+			sequence["CYCLE"],
+			sequence["OWNED_BY"],
+		})
+	}
+	sectionRequests, _ := createSectionRequests(sheetId, 3, "シーケンス一覧", sequenceSectionColumns, rows)
+	requests = append(requests, sectionRequests...)
+
+	return requests
+}
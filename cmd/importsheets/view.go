@@ -0,0 +1,178 @@
+package main
+
+import (
+	"export-db-info/internal/google_internal"
+	"export-db-info/internal/model/google_model"
+	"google.golang.org/api/sheets/v4"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sheetEntry はインデックスページに載せるシート名とシートIDの組です。
+type sheetEntry struct {
+	name string
+	id   int64
+}
+
+// viewColumnSectionColumns はビューのシートの「カラム一覧」セクションの列です。
+var viewColumnSectionColumns = []sectionColumn{
+	{"No", 1},
+	{"カラム名", 4},
+	{"データ型", 3},
+	{"NULL許可", 1},
+	{"コメント", 5},
+}
+
+// viewTitleColor はビューのシートの見出しの背景色です。テーブルのシートと見分けられるよう色を変えています。
+var viewTitleColor = &sheets.Color{Red: 0.1, Green: 0.35, Blue: 0.3}
+
+// createViewSheets は meta/views.csv に記載されたビューごとにシートを作成し、作成したシートの一覧を返します。
+func createViewSheets(sheSrv *sheets.Service, spreadsheetId string, csvDir string) []sheetEntry {
+	viewInfos, err := readMetaCSVRows(csvDir, "views.csv")
+	if err != nil {
+		log.Printf("Unable to read views csv: %v", err)
+		return nil
+	}
+
+	var entries []sheetEntry
+	for _, viewInfo := range viewInfos {
+		viewName := viewInfo["VIEW_NAME"]
+
+		resp, err := sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+			Requests: []*sheets.Request{{
+				AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: viewName}},
+			}},
+		}).Do()
+		if err != nil {
+			log.Printf("Unable to create new sheet: %v", err)
+			continue
+		}
+		if len(resp.Replies) == 0 || resp.Replies[0].AddSheet == nil {
+			log.Fatal("Failed to get the new sheet ID")
+		}
+		sheetId := resp.Replies[0].AddSheet.Properties.SheetId
+		entries = append(entries, sheetEntry{name: viewName, id: sheetId})
+
+		columns, err := readTableCSV(csvDir, "views", viewName)
+		if err != nil {
+			log.Printf("Unable to read view columns csv: %v", err)
+		}
+
+		_, err = sheSrv.Spreadsheets.BatchUpdate(spreadsheetId, &sheets.BatchUpdateSpreadsheetRequest{
+			Requests: createViewSheetRequests(sheetId, viewInfo, columns),
+		}).Do()
+		if err != nil {
+			log.Printf("Unable to create new sheet: %v", err)
+			continue
+		}
+
+		// 3秒間待機(google api のリウエスト制限にかからないように)
+		time.Sleep(3 * time.Second)
+	}
+
+	return entries
+}
+
+// createViewSheetRequests はビューのシートのレイアウトを作成します。
+// 見出し・参照テーブル・プロパティ（セキュリティ・チェックオプション・更新可否）・カラム一覧・定義SQLの順に並べます。
+func createViewSheetRequests(sheetId int64, viewInfo map[string]string, columns []map[string]string) []*sheets.Request {
+	var requests []*sheets.Request
+
+	requests = append(requests, google_internal.CreateSheetLayoutRequest(
+		sheetId,
+		&google_model.RangeOption{StartRow: 0, EndRow: 2, StartCol: 0, EndCol: 3},
+		true,
+		"CENTER",
+		"MIDDLE",
+		viewTitleColor,
+		&sheets.Color{Red: 1, Green: 1, Blue: 1},
+		"ビュー仕様書",
+		"",
+		&sheets.TextFormat{FontSize: 10, Bold: true},
+	)...)
+
+	headerLabels := []property{
+		{"ビュー物理名", viewInfo["VIEW_NAME"]},
+		{"参照テーブル", strings.ReplaceAll(viewInfo["REFERENCED_TABLES"], ",", ", ")},
+	}
+	for i, p := range headerLabels {
+		row := int64(i)
+		requests = append(requests, google_internal.CreateSheetLayoutRequest(
+			sheetId,
+			&google_model.RangeOption{StartRow: row, EndRow: row + 1, StartCol: 3, EndCol: 5},
+			true,
+			"CENTER",
+			"MIDDLE",
+			viewTitleColor,
+			&sheets.Color{Red: 1, Green: 1, Blue: 1},
+			p.label,
+			"",
+			&sheets.TextFormat{FontSize: 10, Bold: false},
+		)...)
+		requests = append(requests, google_internal.CreateSheetLayoutRequest(
+			sheetId,
+			&google_model.RangeOption{StartRow: row, EndRow: row + 1, StartCol: 5, EndCol: 14},
+			true,
+			"LEFT",
+			"MIDDLE",
+			&sheets.Color{Red: 1, Green: 1, Blue: 1},
+			nil,
+			p.value,
+			"",
+			&sheets.TextFormat{FontSize: 10, Bold: false},
+		)...)
+	}
+
+	requests = append(requests, google_internal.CreateSheetLayoutRequest(
+		sheetId,
+		&google_model.RangeOption{StartRow: 2, EndRow: 4, StartCol: 0, EndCol: 2},
+		true,
+		"CENTER",
+		"MIDDLE",
+		viewTitleColor,
+		&sheets.Color{Red: 1, Green: 1, Blue: 1},
+		"内容説明",
+		"",
+		&sheets.TextFormat{FontSize: 10, Bold: false},
+	)...)
+	requests = append(requests, google_internal.CreateSheetLayoutRequest(
+		sheetId,
+		&google_model.RangeOption{StartRow: 2, EndRow: 4, StartCol: 2, EndCol: 14},
+		true,
+		"LEFT",
+		"MIDDLE",
+		&sheets.Color{Red: 1, Green: 1, Blue: 1},
+		nil,
+		viewInfo["COMMENT"],
+		"",
+		&sheets.TextFormat{FontSize: 10, Bold: false},
+	)...)
+
+	propertyRequests, nextRow := createPropertyBlockRequests(sheetId, 5, []property{
+		{"セキュリティ", viewInfo["SECURITY_TYPE"]},
+		{"チェックオプション", viewInfo["CHECK_OPTION"]},
+		{"更新可能", viewInfo["IS_UPDATABLE"]},
+	})
+	requests = append(requests, propertyRequests...)
+
+	var columnRows [][]string
+	for i, col := range columns {
+		columnRows = append(columnRows, []string{
+			strconv.Itoa(i + 1),
+			col["COLUMN_NAME"],
+			col["COLUMN_TYPE"],
+			col["IS_NULLABLE"],
+			col["COMMENT"],
+		})
+	}
+	sectionRequests, nextRow := createSectionRequests(sheetId, nextRow+1, "カラム一覧", viewColumnSectionColumns, columnRows)
+	requests = append(requests, sectionRequests...)
+
+	// 定義SQL
+	definitionRequests, _ := createDefinitionBlockRequests(sheetId, nextRow+1, "定義SQL", viewInfo["VIEW_DEFINITION"], viewTitleColor)
+	requests = append(requests, definitionRequests...)
+
+	return requests
+}
@@ -4,6 +4,8 @@ go 1.20
 
 require (
 	github.com/go-sql-driver/mysql v1.7.1
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.22
 	golang.org/x/oauth2 v0.15.0
 	google.golang.org/api v0.153.0
 )
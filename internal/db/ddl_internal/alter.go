@@ -0,0 +1,517 @@
+package ddl_internal
+
+import (
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"strings"
+)
+
+// parseAlterTable は ALTER TABLE 文の変更内容を、カンマ区切りの順にテーブル定義へ反映します。
+func (s *schema) parseAlterTable(p *parser) error {
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	t := s.findTable(name)
+	if t == nil {
+		return fmt.Errorf("alter table %s: table is not defined", name)
+	}
+
+	for !p.atEnd() {
+		if err := s.parseAlterSpec(p, t); err != nil {
+			return fmt.Errorf("alter table %s: %w", name, err)
+		}
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return nil
+}
+
+// parseAlterSpec は ALTER TABLE の変更内容を1つ解析します。
+func (s *schema) parseAlterSpec(p *parser, t *table) error {
+	switch {
+	case p.accept("ADD"):
+		return p.parseAlterAdd(t)
+	case p.accept("DROP"):
+		return p.parseAlterDrop(t)
+	case p.accept("MODIFY"):
+		p.accept("COLUMN")
+		col, err := p.parseColumn(t)
+		if err != nil {
+			return err
+		}
+		return t.replaceColumn(col.Name, col, p.parseColumnPosition())
+	case p.accept("CHANGE"):
+		p.accept("COLUMN")
+		oldName, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		col, err := p.parseColumn(t)
+		if err != nil {
+			return err
+		}
+		if err := t.replaceColumn(oldName, col, p.parseColumnPosition()); err != nil {
+			return err
+		}
+		s.renameColumnReferences(t, oldName, col.Name)
+	case p.accept("RENAME"):
+		return s.parseAlterRename(p, t)
+	case p.accept("ALTER"):
+		if p.accept("INDEX") {
+			name, err := p.parseName()
+			if err != nil {
+				return err
+			}
+			if idx := t.findIndex(name); idx != nil {
+				p.parseIndexOptions(idx)
+			}
+			p.skipElement()
+			return nil
+		}
+		if p.accept("CHECK") || p.accept("CONSTRAINT") {
+			name, err := p.parseName()
+			if err != nil {
+				return err
+			}
+			if check := t.findCheck(name); check != nil {
+				switch {
+				case p.accept("NOT", "ENFORCED"):
+					check.IsEnforced = false
+				case p.accept("ENFORCED"):
+					check.IsEnforced = true
+				}
+			}
+			p.skipElement()
+			return nil
+		}
+		p.accept("COLUMN")
+		name, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		col := t.findColumn(name)
+		if col == nil {
+			return fmt.Errorf("column %s is not defined", name)
+		}
+		switch {
+		case p.accept("SET", "DEFAULT"):
+			value, isExpression, err := p.parseDefault()
+			if err != nil {
+				return err
+			}
+			setDefault(col, value, isExpression)
+		case p.accept("DROP", "DEFAULT"):
+			setDefault(col, nil, false)
+		}
+		p.skipElement()
+	case p.accept("PARTITION", "BY"):
+		partitioning, err := p.parsePartitionBy()
+		if err != nil {
+			return err
+		}
+		t.partitioning = partitioning
+	case p.peek().is("COALESCE", "REORGANIZE", "REMOVE"):
+		return p.parseAlterPartition(t, strings.ToUpper(p.next().text))
+	case p.peek().is("ALGORITHM", "LOCK", "FORCE", "ORDER", "CONVERT", "ENABLE", "DISABLE", "DISCARD", "IMPORT",
+		"VALIDATION", "WITHOUT", "WITH", "EXCHANGE", "ANALYZE", "CHECK", "OPTIMIZE",
+		"REBUILD", "REPAIR", "TRUNCATE", "PARTITION"):
+		// テーブル定義の内容に影響しない指定
+		p.skipElement()
+	default:
+		p.parseTableOption(t)
+	}
+
+	return nil
+}
+
+// parseAlterAdd は ALTER TABLE ... ADD 以降のカラム・インデックス・制約の追加を解析します。
+func (p *parser) parseAlterAdd(t *table) error {
+	if p.peek().is("PARTITION") {
+		return p.parseAlterPartition(t, "ADD")
+	}
+
+	isColumn := p.accept("COLUMN")
+
+	// ADD [COLUMN] (col1 ..., col2 ...)
+	if p.acceptSymbol("(") {
+		for {
+			col, err := p.parseColumn(t)
+			if err != nil {
+				return err
+			}
+			if err := t.addColumn(col, columnPosition{}); err != nil {
+				return err
+			}
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		return p.expectSymbol(")")
+	}
+
+	if !isColumn && p.peek().is("CONSTRAINT", "PRIMARY", "UNIQUE", "INDEX", "KEY", "FULLTEXT", "SPATIAL", "FOREIGN", "CHECK") {
+		return p.parseTableElement(t)
+	}
+
+	col, err := p.parseColumn(t)
+	if err != nil {
+		return err
+	}
+	return t.addColumn(col, p.parseColumnPosition())
+}
+
+// parseAlterDrop は ALTER TABLE ... DROP 以降のカラム・インデックス・制約の削除を解析します。
+func (p *parser) parseAlterDrop(t *table) error {
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		t.primaryKey = nil
+		return nil
+	case p.peek().is("PARTITION"):
+		return p.parseAlterPartition(t, "DROP")
+	}
+
+	var kind string
+	switch {
+	case p.accept("FOREIGN", "KEY"):
+		kind = "FOREIGN KEY"
+	case p.accept("INDEX"), p.accept("KEY"):
+		kind = "INDEX"
+	case p.accept("CHECK"):
+		kind = "CHECK"
+	case p.accept("CONSTRAINT"):
+		kind = "CONSTRAINT"
+	default:
+		p.accept("COLUMN")
+		kind = "COLUMN"
+	}
+	p.accept("IF", "EXISTS")
+
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	p.skipElement()
+
+	switch kind {
+	case "FOREIGN KEY":
+		t.dropForeignKey(name)
+	case "INDEX":
+		t.dropIndex(name)
+	case "CHECK":
+		t.dropCheck(name)
+	case "CONSTRAINT":
+		t.dropForeignKey(name)
+		t.dropIndex(name)
+		t.dropCheck(name)
+	case "COLUMN":
+		t.dropColumn(name)
+	}
+	return nil
+}
+
+// parseAlterRename は ALTER TABLE ... RENAME 以降のテーブル・カラム・インデックスの名前変更を解析します。
+func (s *schema) parseAlterRename(p *parser, t *table) error {
+	switch {
+	case p.accept("COLUMN"):
+		oldName, newName, err := p.parseRenamePair()
+		if err != nil {
+			return err
+		}
+		col := t.findColumn(oldName)
+		if col == nil {
+			return fmt.Errorf("column %s is not defined", oldName)
+		}
+		col.Name = newName
+		s.renameColumnReferences(t, oldName, newName)
+	case p.accept("INDEX"), p.accept("KEY"):
+		oldName, newName, err := p.parseRenamePair()
+		if err != nil {
+			return err
+		}
+		if idx := t.findIndex(oldName); idx != nil {
+			idx.name = newName
+		}
+	default:
+		_ = p.accept("TO") || p.accept("AS")
+		newName, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		s.renameTable(t, newName)
+	}
+	return nil
+}
+
+// parseRenamePair は「旧名 TO 新名」を読み取ります。
+func (p *parser) parseRenamePair() (string, string, error) {
+	oldName, err := p.parseName()
+	if err != nil {
+		return "", "", err
+	}
+	if err := p.expect("TO"); err != nil {
+		return "", "", err
+	}
+	newName, err := p.parseName()
+	if err != nil {
+		return "", "", err
+	}
+	return oldName, newName, nil
+}
+
+// columnPosition は ALTER TABLE で指定されたカラムの位置（FIRST / AFTER）です。
+type columnPosition struct {
+	first bool
+	after string
+}
+
+func (p *parser) parseColumnPosition() columnPosition {
+	switch {
+	case p.accept("FIRST"):
+		return columnPosition{first: true}
+	case p.accept("AFTER"):
+		name, _ := p.parseName()
+		return columnPosition{after: name}
+	}
+	return columnPosition{}
+}
+
+// parseDropTable は DROP TABLE 文を解析します。
+func (s *schema) parseDropTable(p *parser) error {
+	p.accept("IF", "EXISTS")
+	for {
+		name, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		s.dropTable(name)
+		if !p.acceptSymbol(",") {
+			return nil
+		}
+	}
+}
+
+// parseRenameTable は RENAME TABLE a TO b [, c TO d] 文を解析します。
+func (s *schema) parseRenameTable(p *parser) error {
+	for {
+		oldName, newName, err := p.parseRenamePair()
+		if err != nil {
+			return err
+		}
+		t := s.findTable(oldName)
+		if t == nil {
+			// RENAME TABLE はビューの名前変更にも使われる
+			v := s.findView(oldName)
+			if v == nil {
+				return fmt.Errorf("rename table %s: table is not defined", oldName)
+			}
+			v.name = newName
+		} else {
+			s.renameTable(t, newName)
+		}
+		if !p.acceptSymbol(",") {
+			return nil
+		}
+	}
+}
+
+// parseCreateIndex は CREATE [UNIQUE | FULLTEXT | SPATIAL] INDEX 文を解析します。
+func (s *schema) parseCreateIndex(p *parser, idx *index) error {
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	idx.name = name
+	if p.accept("USING") {
+		idx.typ = strings.ToUpper(p.next().text)
+	}
+	if err := p.expect("ON"); err != nil {
+		return err
+	}
+	tableName, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	t := s.findTable(tableName)
+	if t == nil {
+		return fmt.Errorf("create index %s: table %s is not defined", name, tableName)
+	}
+	if idx.parts, err = p.parseKeyParts(); err != nil {
+		return err
+	}
+	p.parseIndexOptions(idx)
+	t.addIndex(idx)
+	return nil
+}
+
+// parseDropIndex は DROP INDEX name ON table 文を解析します。
+func (s *schema) parseDropIndex(p *parser) error {
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("ON"); err != nil {
+		return err
+	}
+	tableName, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	if t := s.findTable(tableName); t != nil {
+		t.dropIndex(name)
+	}
+	return nil
+}
+
+func (s *schema) dropTable(name string) {
+	for i, t := range s.tables {
+		if strings.EqualFold(t.name, name) {
+			s.tables = append(s.tables[:i], s.tables[i+1:]...)
+			return
+		}
+	}
+}
+
+// renameTable はテーブル名を変更し、他のテーブルの外部キーの参照先と自動で付けたCHECK制約の名前も合わせて変更します。
+func (s *schema) renameTable(t *table, newName string) {
+	for _, other := range s.tables {
+		for _, fk := range other.foreignKeys {
+			if strings.EqualFold(fk.refTable, t.name) {
+				fk.refTable = newName
+			}
+		}
+	}
+	t.renameChecks(t.name, newName)
+	t.name = newName
+}
+
+// renameColumnReferences はカラム名の変更を主キー・インデックス・外部キー（他テーブルからの参照を含む）へ反映します。
+func (s *schema) renameColumnReferences(t *table, oldName, newName string) {
+	if strings.EqualFold(oldName, newName) {
+		return
+	}
+	for _, idx := range t.allIndexes() {
+		for _, part := range idx.parts {
+			if strings.EqualFold(part.Name, oldName) {
+				part.Name = newName
+			}
+		}
+	}
+	for _, fk := range t.foreignKeys {
+		renameIn(fk.columns, oldName, newName)
+	}
+	for _, other := range s.tables {
+		for _, fk := range other.foreignKeys {
+			if strings.EqualFold(fk.refTable, t.name) {
+				renameIn(fk.refColumns, oldName, newName)
+			}
+		}
+	}
+}
+
+func renameIn(names []string, oldName, newName string) {
+	for i, name := range names {
+		if strings.EqualFold(name, oldName) {
+			names[i] = newName
+		}
+	}
+}
+
+// addColumn はカラムを指定された位置（未指定の場合は末尾）に追加します。
+func (t *table) addColumn(col *sql_model.Column, pos columnPosition) error {
+	if t.findColumn(col.Name) != nil {
+		return fmt.Errorf("column %s is already defined", col.Name)
+	}
+	return t.insertColumn(col, pos, len(t.columns))
+}
+
+// replaceColumn は既存のカラム定義を置き換えます。位置の指定がない場合は元の位置を維持します。
+func (t *table) replaceColumn(oldName string, col *sql_model.Column, pos columnPosition) error {
+	i := t.columnIndex(oldName)
+	if i < 0 {
+		return fmt.Errorf("column %s is not defined", oldName)
+	}
+	t.columns = append(t.columns[:i], t.columns[i+1:]...)
+	return t.insertColumn(col, pos, i)
+}
+
+func (t *table) insertColumn(col *sql_model.Column, pos columnPosition, defaultIndex int) error {
+	i := defaultIndex
+	switch {
+	case pos.first:
+		i = 0
+	case pos.after != "":
+		after := t.columnIndex(pos.after)
+		if after < 0 {
+			return fmt.Errorf("column %s specified in AFTER is not defined", pos.after)
+		}
+		i = after + 1
+	}
+	t.columns = append(t.columns[:i], append([]*sql_model.Column{col}, t.columns[i:]...)...)
+	return nil
+}
+
+func (t *table) columnIndex(name string) int {
+	for i, col := range t.columns {
+		if strings.EqualFold(col.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// dropColumn はカラムを削除します。MySQLと同様に、インデックスからもカラムを取り除き、空になったインデックスは削除します。
+func (t *table) dropColumn(name string) {
+	if i := t.columnIndex(name); i >= 0 {
+		t.columns = append(t.columns[:i], t.columns[i+1:]...)
+	}
+
+	if t.primaryKey != nil && !t.primaryKey.removeColumn(name) {
+		t.primaryKey = nil
+	}
+
+	var indexes []*index
+	for _, idx := range t.indexes {
+		if idx.removeColumn(name) {
+			indexes = append(indexes, idx)
+		}
+	}
+	t.indexes = indexes
+
+	var foreignKeys []*foreignKey
+	for _, fk := range t.foreignKeys {
+		if len(removeName(fk.columns, name)) == len(fk.columns) {
+			foreignKeys = append(foreignKeys, fk)
+		}
+	}
+	t.foreignKeys = foreignKeys
+}
+
+func (t *table) dropIndex(name string) {
+	for i, idx := range t.indexes {
+		if strings.EqualFold(idx.name, name) {
+			t.indexes = append(t.indexes[:i], t.indexes[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *table) dropForeignKey(name string) {
+	for i, fk := range t.foreignKeys {
+		if strings.EqualFold(fk.name, name) {
+			t.foreignKeys = append(t.foreignKeys[:i], t.foreignKeys[i+1:]...)
+			return
+		}
+	}
+}
+
+func removeName(names []string, name string) []string {
+	var result []string
+	for _, n := range names {
+		if !strings.EqualFold(n, name) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package ddl_internal
+
+import (
+	"export-db-info/internal/model/sql_model"
+	"strconv"
+	"strings"
+)
+
+// parseCheck は CHECK 以降のCHECK制約を読み取り、テーブルへ追加します。
+// 名前を省略した場合は、MySQLと同様に「テーブル名_chk_連番」と名前を付けます。
+func (p *parser) parseCheck(t *table, name string) error {
+	expression, err := p.parseParenthesized()
+	if err != nil {
+		return err
+	}
+	check := &sql_model.Check{Name: name, Expression: joinTokens(expression), IsEnforced: true}
+	switch {
+	case p.accept("NOT", "ENFORCED"):
+		check.IsEnforced = false
+	case p.accept("ENFORCED"):
+	}
+
+	if check.Name == "" {
+		check.Name = t.nextCheckName()
+	}
+	t.dropCheck(check.Name)
+	t.checks = append(t.checks, check)
+	return nil
+}
+
+// nextCheckName は名前を省略したCHECK制約に付ける名前を返します。
+func (t *table) nextCheckName() string {
+	prefix := t.name + "_chk_"
+	n := 0
+	for _, check := range t.checks {
+		if len(check.Name) <= len(prefix) || !strings.EqualFold(check.Name[:len(prefix)], prefix) {
+			continue
+		}
+		if i, err := strconv.Atoi(check.Name[len(prefix):]); err == nil && i > n {
+			n = i
+		}
+	}
+	return prefix + strconv.Itoa(n+1)
+}
+
+func (t *table) findCheck(name string) *sql_model.Check {
+	for _, check := range t.checks {
+		if strings.EqualFold(check.Name, name) {
+			return check
+		}
+	}
+	return nil
+}
+
+func (t *table) dropCheck(name string) {
+	for i, check := range t.checks {
+		if strings.EqualFold(check.Name, name) {
+			t.checks = append(t.checks[:i], t.checks[i+1:]...)
+			return
+		}
+	}
+}
+
+// renameChecks はテーブル名の変更に合わせて、自動で付けたCHECK制約の名前を変更します。
+func (t *table) renameChecks(oldName, newName string) {
+	prefix := oldName + "_chk_"
+	for _, check := range t.checks {
+		if len(check.Name) > len(prefix) && strings.EqualFold(check.Name[:len(prefix)], prefix) {
+			check.Name = newName + "_chk_" + check.Name[len(prefix):]
+		}
+	}
+}
+
+// cloneChecks はCHECK制約を複製します。
+func cloneChecks(src []*sql_model.Check) []*sql_model.Check {
+	var checks []*sql_model.Check
+	for _, check := range src {
+		copied := *check
+		checks = append(checks, &copied)
+	}
+	return checks
+}
+
+// characterTypes は文字セット・照合順序を持つデータ型です。
+var characterTypes = []string{"char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set"}
+
+// applyCharset は文字列型のカラムの文字セット・照合順序を、省略時はテーブルの既定値で補います。
+// 照合順序の名前は「文字セット_...」の形式のため、文字セットのみ省略した場合は照合順序から求めます。
+func (t *table) applyCharset(col *sql_model.Column) {
+	dataType := col.Type
+	if i := strings.IndexAny(dataType, "( "); i >= 0 {
+		dataType = dataType[:i]
+	}
+	if !containsFold(characterTypes, dataType) {
+		return
+	}
+
+	if col.CharacterSet == "" && col.Collation == "" {
+		col.CharacterSet, col.Collation = t.options["CHARSET"], t.options["COLLATE"]
+	} else if col.Collation == "" && strings.EqualFold(col.CharacterSet, t.options["CHARSET"]) {
+		col.Collation = t.options["COLLATE"]
+	}
+	if col.CharacterSet == "" && col.Collation != "" {
+		col.CharacterSet, _, _ = strings.Cut(col.Collation, "_")
+	}
+}
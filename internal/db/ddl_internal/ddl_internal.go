@@ -0,0 +1,72 @@
+package ddl_internal
+
+import (
+	"context"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	inspector.Register("mysqldump", NewDumpInspector)
+}
+
+// DumpInspector は mysqldump --no-data などで出力したDDLファイルからスキーマ情報を取得します。
+// データベースへの接続は行いません。
+type DumpInspector struct {
+	cfg *inspector.Config
+}
+
+// NewDumpInspector は接続設定からDDLファイル用のSchemaInspectorを生成します。
+func NewDumpInspector(cfg *inspector.Config) (inspector.SchemaInspector, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("DB_PATH must be set to the DDL file path")
+	}
+	return &DumpInspector{cfg: cfg}, nil
+}
+
+// dumpHeaderPattern は mysqldump が出力するヘッダーコメントからデータベース名を取り出します。
+var dumpHeaderPattern = regexp.MustCompile(`(?m)^-- Host: .*\sDatabase: (\S+)`)
+
+// Inspect はDDLファイルを読み込み、CREATE TABLE 文からテーブルとカラムの情報を組み立てます。
+func (i *DumpInspector) Inspect(ctx context.Context) (*sql_model.DB, error) {
+	src, err := os.ReadFile(i.cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &schema{name: i.cfg.Database}
+	if err := s.parse(string(src)); err != nil {
+		return nil, fmt.Errorf("%s: %w", i.cfg.Path, err)
+	}
+
+	// データベース名は DB_DATABASE → USE 文 → ダンプのヘッダー → ファイル名 の順に決定する
+	if s.name == "" {
+		if m := dumpHeaderPattern.FindSubmatch(src); m != nil {
+			s.name = string(m[1])
+		}
+	}
+	if s.name == "" {
+		s.name = strings.TrimSuffix(filepath.Base(i.cfg.Path), filepath.Ext(i.cfg.Path))
+	}
+
+	return s.toModel(), nil
+}
+
+// parse はSQLを文単位に分割し、順にスキーマ定義へ反映します。
+func (s *schema) parse(src string) error {
+	for _, stmt := range splitStatements(src) {
+		tokens, err := tokenize(stmt)
+		if err != nil {
+			return err
+		}
+		if err := s.parseStatement(stmt, tokens); err != nil {
+			return err
+		}
+	}
+	return nil
+}
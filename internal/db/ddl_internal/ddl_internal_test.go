@@ -0,0 +1,346 @@
+package ddl_internal
+
+import (
+	"context"
+	"encoding/json"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// inspectDump は testdata 配下のダンプファイルを読み込みます。
+func inspectDump(t *testing.T, path string) *sql_model.DB {
+	t.Helper()
+	i, err := NewDumpInspector(&inspector.Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := i.Inspect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func tableOf(t *testing.T, db *sql_model.DB, name string) *sql_model.Table {
+	t.Helper()
+	for _, table := range db.Tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	t.Fatalf("table %q not found", name)
+	return nil
+}
+
+func columnOf(t *testing.T, table *sql_model.Table, name string) *sql_model.Column {
+	t.Helper()
+	for _, col := range table.Columns {
+		if col.Name == name {
+			return col
+		}
+	}
+	t.Fatalf("column %s.%q not found", table.Name, name)
+	return nil
+}
+
+func indexOf(t *testing.T, table *sql_model.Table, name string) *sql_model.Index {
+	t.Helper()
+	for _, index := range table.Indexes {
+		if index.Name == name {
+			return index
+		}
+	}
+	t.Fatalf("index %s.%q not found", table.Name, name)
+	return nil
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// 文の分割でバージョン付きコメントを残し、DELIMITER の切り替えに従うこと
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "comments",
+			src:  "SELECT 1 /* a; b */;\n-- c;\nSELECT 2;# d;\n",
+			want: []string{"SELECT 1 /* a; b */", "-- c;\nSELECT 2", "# d;"},
+		},
+		{
+			name: "version comment",
+			src:  "/*!40101 SET NAMES utf8mb4 */;\nCREATE TABLE t (a int)\n/*!50100 PARTITION BY HASH (a) */;",
+			want: []string{"/*!40101 SET NAMES utf8mb4 */", "CREATE TABLE t (a int)\n/*!50100 PARTITION BY HASH (a) */"},
+		},
+		{
+			name: "delimiter",
+			src:  "DELIMITER ;;\nCREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END ;;\nDELIMITER ;\nSELECT 3;",
+			want: []string{"CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END", "SELECT 3"},
+		},
+		{
+			name: "quoted delimiter",
+			src:  "SELECT ';', `a;b`, \"c;d\";SELECT 'it''s';",
+			want: []string{"SELECT ';', `a;b`, \"c;d\"", "SELECT 'it''s'"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.src); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ダンプのヘッダーからデータベース名を取得し、テーブルを名前順に並べること
+func TestDumpTables(t *testing.T) {
+	db := inspectDump(t, "testdata/dump.sql")
+	if db.Name != "shop" {
+		t.Errorf("got database name %q, want %q", db.Name, "shop")
+	}
+	var names []string
+	for _, table := range db.Tables {
+		names = append(names, table.Name)
+	}
+	if want := []string{"audit", "order`items", "users"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got tables %q, want %q", names, want)
+	}
+
+	users := tableOf(t, db, "users")
+	if users.Engine != "InnoDB" || users.Collation != "utf8mb4_0900_ai_ci" || users.Comment != "利用者" {
+		t.Errorf("got engine %q, collation %q, comment %q", users.Engine, users.Collation, users.Comment)
+	}
+	if users.AutoIncrement == nil || *users.AutoIncrement != 42 {
+		t.Errorf("got auto increment %v, want 42", users.AutoIncrement)
+	}
+}
+
+// カラム定義の属性を読み取ること
+func TestDumpColumns(t *testing.T) {
+	db := inspectDump(t, "testdata/dump.sql")
+	tests := []struct {
+		table string
+		want  sql_model.Column
+	}{
+		{
+			table: "users",
+			want: sql_model.Column{
+				Name: "id", Type: "bigint unsigned", IsPrimaryKey: true, IsIndexed: true,
+				Extra: "auto_increment", Comment: "ユーザーID",
+			},
+		},
+		{
+			table: "users",
+			want: sql_model.Column{
+				Name: "email", Type: "varchar(255)", IsUnique: true, IsIndexed: true,
+				CharacterSet: "utf8mb4", Collation: "utf8mb4_bin",
+			},
+		},
+		{
+			table: "users",
+			want: sql_model.Column{
+				Name: "bio", Type: "text", IsNullable: true, IsUnique: true, UniquePrefixLength: 10, IsIndexed: true,
+				CharacterSet: "utf8mb4", Collation: "utf8mb4_0900_ai_ci",
+			},
+		},
+		{
+			table: "users",
+			want: sql_model.Column{
+				Name: "last_name", Type: "varchar(50)", Default: strPtr("it's"),
+				CharacterSet: "utf8mb4", Collation: "utf8mb4_0900_ai_ci",
+			},
+		},
+		{
+			table: "users",
+			want: sql_model.Column{
+				Name: "full_name", Type: "varchar(101)", IsNullable: true,
+				Extra: "VIRTUAL GENERATED", Generated: "VIRTUAL", Expression: "concat(`first_name`, _utf8mb4 ' ', `last_name`)",
+				CharacterSet: "utf8mb4", Collation: "utf8mb4_0900_ai_ci",
+			},
+		},
+		{
+			table: "users",
+			want: sql_model.Column{
+				Name: "status", Type: "enum('active','it''s off')", Default: strPtr("active"),
+				CharacterSet: "utf8mb4", Collation: "utf8mb4_0900_ai_ci", EnumValues: []string{"active", "it's off"},
+			},
+		},
+		{
+			table: "users",
+			want: sql_model.Column{
+				Name: "created_at", Type: "datetime", IsIndexed: true,
+				Default: strPtr("CURRENT_TIMESTAMP"), IsDefaultExpression: true, Extra: "DEFAULT_GENERATED",
+			},
+		},
+		{
+			table: "users",
+			want: sql_model.Column{
+				Name: "updated_at", Type: "timestamp", IsNullable: true, Extra: "on update CURRENT_TIMESTAMP",
+			},
+		},
+		{
+			table: "order`items",
+			want: sql_model.Column{
+				Name: "user_id", Type: "bigint unsigned", IsPrimaryKey: true, IsIndexed: true,
+				IsForeign: true, ForeignKeyTable: "users", ForeignKeyColumn: "id",
+			},
+		},
+		{
+			table: "order`items",
+			want:  sql_model.Column{Name: "line`no", Type: "int", IsPrimaryKey: true, IsIndexed: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.table+"."+tt.want.Name, func(t *testing.T) {
+			got := columnOf(t, tableOf(t, db, tt.table), tt.want.Name)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("got %s, want %s", dump(got), dump(tt.want))
+			}
+		})
+	}
+}
+
+// プレフィックス・降順・関数インデックスを読み取ること
+func TestDumpIndexes(t *testing.T) {
+	db := inspectDump(t, "testdata/dump.sql")
+	tests := []struct {
+		table     string
+		name      string
+		isPrimary bool
+		isUnique  bool
+		columns   []sql_model.IndexColumn
+	}{
+		{
+			table: "users", name: "PRIMARY", isPrimary: true, isUnique: true,
+			columns: []sql_model.IndexColumn{{Name: "tenant_id"}, {Name: "id"}},
+		},
+		{
+			table: "users", name: "uk_bio", isUnique: true,
+			columns: []sql_model.IndexColumn{{Name: "bio", Length: 10}},
+		},
+		{
+			table: "users", name: "idx_created",
+			columns: []sql_model.IndexColumn{{Name: "created_at", Descending: true}},
+		},
+		{
+			table: "users", name: "idx_lower_email",
+			columns: []sql_model.IndexColumn{{Expression: "lower(`email`)"}},
+		},
+		{
+			table: "order`items", name: "PRIMARY", isPrimary: true, isUnique: true,
+			columns: []sql_model.IndexColumn{{Name: "tenant_id"}, {Name: "user_id"}, {Name: "line`no"}, {Name: "ordered_on"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.table+"."+tt.name, func(t *testing.T) {
+			index := indexOf(t, tableOf(t, db, tt.table), tt.name)
+			if index.IsPrimary != tt.isPrimary || index.IsUnique != tt.isUnique {
+				t.Errorf("got primary %v unique %v, want primary %v unique %v", index.IsPrimary, index.IsUnique, tt.isPrimary, tt.isUnique)
+			}
+			var columns []sql_model.IndexColumn
+			for _, col := range index.Columns {
+				columns = append(columns, *col)
+			}
+			if !reflect.DeepEqual(columns, tt.columns) {
+				t.Errorf("got columns %s, want %s", dump(columns), dump(tt.columns))
+			}
+		})
+	}
+}
+
+// 制約・パーティション・トリガー・ルーチンを読み取ること
+func TestDumpConstraints(t *testing.T) {
+	db := inspectDump(t, "testdata/dump.sql")
+	users := tableOf(t, db, "users")
+	items := tableOf(t, db, "order`items")
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{
+			name: "check",
+			got:  users.Checks,
+			want: []*sql_model.Check{
+				{Name: "chk_age", Expression: "(`age` >= 0)", IsEnforced: true},
+				{Name: "users_chk_1", Expression: "(`status` <> _utf8mb4 'x')", IsEnforced: false},
+			},
+		},
+		{
+			name: "composite foreign key",
+			got:  items.ForeignKeys,
+			want: []*sql_model.ForeignKey{{
+				Name:              "fk_user",
+				Columns:           []string{"tenant_id", "user_id"},
+				ReferencedTable:   "users",
+				ReferencedColumns: []string{"tenant_id", "id"},
+				UpdateRule:        "SET NULL",
+				DeleteRule:        "CASCADE",
+			}},
+		},
+		{
+			name: "partitioning in version comment",
+			got:  items.Partitioning,
+			want: &sql_model.Partitioning{
+				Method:     "RANGE",
+				Expression: "year(`ordered_on`)",
+				Partitions: []*sql_model.Partition{
+					{Name: "p2023", Bound: "LESS THAN (2024)"},
+					{Name: "p2024", Bound: "LESS THAN (2025)"},
+					{Name: "pmax", Bound: "LESS THAN MAXVALUE"},
+				},
+			},
+		},
+		{
+			name: "trigger in delimiter block",
+			got:  users.Triggers,
+			want: []*sql_model.Trigger{{
+				Name:      "users_bi",
+				Timing:    "BEFORE",
+				Event:     "INSERT",
+				Order:     1,
+				Statement: "BEGIN\n  SET NEW.email = lower(NEW.email);\nEND",
+				Definer:   "root@localhost",
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %s, want %s", dump(tt.got), dump(tt.want))
+			}
+		})
+	}
+
+	if len(db.Routines) != 1 {
+		t.Fatalf("got %d routines, want 1", len(db.Routines))
+	}
+	routine := db.Routines[0]
+	if routine.Name != "full_name_of" || routine.Type != "FUNCTION" || routine.ReturnType != "varchar(101)" ||
+		routine.DataAccess != "READS SQL DATA" || routine.Definer != "root@localhost" || routine.SecurityType != "DEFINER" {
+		t.Errorf("got routine %+v", *routine)
+	}
+	if want := []*sql_model.Parameter{{Name: "p_id", Mode: "IN", DataType: "bigint"}}; !reflect.DeepEqual(routine.Parameters, want) {
+		t.Errorf("got parameters %s, want %s", dump(routine.Parameters), dump(want))
+	}
+	if !strings.HasPrefix(routine.Definition, "BEGIN\n  DECLARE result VARCHAR(101);") || !strings.HasSuffix(routine.Definition, "END") {
+		t.Errorf("got definition %q", routine.Definition)
+	}
+}
+
+// dump は比較に失敗した値をポインタの中身まで読める形で文字列にします。
+func dump(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return string(b)
+}
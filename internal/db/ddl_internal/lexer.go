@@ -0,0 +1,264 @@
+package ddl_internal
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF         tokenKind = iota
+	tokenIdent                 // 識別子・キーワード
+	tokenQuotedIdent           // バッククォートで囲まれた識別子
+	tokenString                // 文字列リテラル
+	tokenNumber                // 数値リテラル
+	tokenSymbol                // 記号
+)
+
+// token はDDLを字句解析した結果の1トークンを表します。
+type token struct {
+	kind tokenKind
+	text string // 引用符を外した値
+	raw  string // ソース上の表記
+	pos  int    // 文中での開始位置（バイト）
+}
+
+// is はトークンが指定したキーワード（大文字小文字を区別しない）のいずれかかを判定します。
+func (t token) is(keywords ...string) bool {
+	if t.kind != tokenIdent {
+		return false
+	}
+	for _, kw := range keywords {
+		if strings.EqualFold(t.text, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSymbol はトークンが指定した記号かを判定します。
+func (t token) isSymbol(symbol string) bool {
+	return t.kind == tokenSymbol && t.text == symbol
+}
+
+// isName はトークンが識別子として使えるかを判定します。
+func (t token) isName() bool {
+	return t.kind == tokenIdent || t.kind == tokenQuotedIdent || t.kind == tokenString
+}
+
+// tokenize は1つのSQL文をトークンに分割します。
+// バージョン付きコメント（/*!40101 ... */）の中身はMySQLと同様にSQLとして扱い、それ以外のコメントは読み飛ばします。
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	inVersionComment := false
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#' || (c == '-' && strings.HasPrefix(src[i:], "--") && (i+2 == len(src) || isSpace(src[i+2]))):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*!"):
+			i += 3
+			for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+				i++
+			}
+			inVersionComment = true
+		case strings.HasPrefix(src[i:], "*/") && inVersionComment:
+			i += 2
+			inVersionComment = false
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 4
+		case c == '`':
+			text, n, err := readQuoted(src[i:], '`')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenQuotedIdent, text: text, raw: src[i : i+n], pos: i})
+			i += n
+		case c == '\'' || c == '"':
+			text, n, err := readQuoted(src[i:], c)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, raw: src[i : i+n], pos: i})
+			i += n
+		case c >= '0' && c <= '9' || (c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9'):
+			start := i
+			for i < len(src) && (isIdentChar(src[i]) || src[i] == '.' ||
+				((src[i] == '+' || src[i] == '-') && (src[i-1] == 'e' || src[i-1] == 'E'))) {
+				i++
+			}
+			// 数字で始まる識別子（例: 1st_column）も許容する
+			kind := tokenNumber
+			if text := strings.ToLower(src[start:i]); !strings.HasPrefix(text, "0x") && !strings.HasPrefix(text, "0b") &&
+				strings.IndexFunc(text, func(r rune) bool { return unicode.IsLetter(r) && r != 'e' }) >= 0 {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: src[start:i], raw: src[start:i], pos: start})
+		case isIdentChar(c) || c == '@' || c >= 0x80:
+			start := i
+			i++
+			for i < len(src) && (isIdentChar(src[i]) || src[i] == '@' || src[i] >= 0x80) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[start:i], raw: src[start:i], pos: start})
+		default:
+			op := readOperator(src[i:])
+			tokens = append(tokens, token{kind: tokenSymbol, text: op, raw: op, pos: i})
+			i += len(op)
+		}
+	}
+
+	return tokens, nil
+}
+
+// readOperator は複数文字の演算子を考慮して記号を1つ読み取ります。
+func readOperator(src string) string {
+	for _, op := range []string{"->>", "<=>", "<=", ">=", "<>", "!=", "||", "&&", ":=", "<<", ">>", "->"} {
+		if strings.HasPrefix(src, op) {
+			return op
+		}
+	}
+	return src[:1]
+}
+
+// readQuoted は引用符で囲まれた文字列を読み取り、エスケープを解除した値と消費したバイト数を返します。
+func readQuoted(src string, quote byte) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == quote && i+1 < len(src) && src[i+1] == quote:
+			b.WriteByte(quote)
+			i++
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && quote != '`' && i+1 < len(src):
+			i++
+			switch src[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			default:
+				b.WriteByte(src[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted string: %.20s", src)
+}
+
+// splitStatements はSQLファイルの内容を文単位に分割します。
+// mysqldumpがトリガー等の出力に使う DELIMITER コマンドにも対応します。
+func splitStatements(src string) []string {
+	var statements []string
+	delimiter := ";"
+	start := 0
+	atLineStart := true
+
+	flush := func(end int) {
+		if stmt := strings.TrimSpace(src[start:end]); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		// DELIMITER コマンドは行頭のみで有効
+		if atLineStart {
+			j := i
+			for j < len(src) && (src[j] == ' ' || src[j] == '\t') {
+				j++
+			}
+			if len(src)-j > 10 && strings.EqualFold(src[j:j+9], "DELIMITER") && isSpace(src[j+9]) {
+				flush(i)
+				end := strings.IndexByte(src[j:], '\n')
+				if end < 0 {
+					end = len(src) - j
+				}
+				if fields := strings.Fields(src[j+9 : j+end]); len(fields) > 0 {
+					delimiter = fields[0]
+				}
+				i = j + end
+				start = i
+				continue
+			}
+		}
+		atLineStart = c == '\n'
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			_, n, err := readQuoted(src[i:], c)
+			if err != nil {
+				i = len(src)
+				continue
+			}
+			i += n
+		case c == '#' || (c == '-' && strings.HasPrefix(src[i:], "--") && (i+2 == len(src) || isSpace(src[i+2]))):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*") && !strings.HasPrefix(src[i:], "/*!"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+				continue
+			}
+			i += end + 4
+		case strings.HasPrefix(src[i:], delimiter):
+			flush(i)
+			i += len(delimiter)
+			start = i
+		default:
+			i++
+		}
+	}
+	flush(len(src))
+
+	return statements
+}
+
+// joinTokens はトークン列を空白で区切って元のSQL表記に近い文字列へ戻します。
+func joinTokens(tokens []token) string {
+	var b strings.Builder
+	for i, t := range tokens {
+		if i > 0 {
+			prev := tokens[i-1]
+			if !(prev.isSymbol("(") || prev.isSymbol(".") || t.isSymbol(")") || t.isSymbol(",") || t.isSymbol(".") ||
+				(t.isSymbol("(") && prev.kind == tokenIdent && !prev.is(sqlKeywords...))) {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(t.raw)
+	}
+	return b.String()
+}
+
+// sqlKeywords は直後の「(」を関数呼び出しとみなさないキーワードです。
+var sqlKeywords = []string{"FROM", "JOIN", "ON", "IN", "AND", "OR", "NOT", "WHERE", "AS", "EXISTS", "USING",
+	"SELECT", "WHEN", "THEN", "ELSE", "UNION", "ALL", "ANY", "SOME", "BY", "HAVING", "RETURN"}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,164 @@
+package ddl_internal
+
+import (
+	"context"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	inspector.Register("migrations", NewMigrationsInspector)
+}
+
+// MigrationsInspector はマイグレーションファイルのDDLを順に適用し、最終的なスキーマ情報を組み立てます。
+// golang-migrate 形式（{version}_{title}.up.sql）と Flyway 形式（V{version}__{description}.sql / R__{description}.sql）に対応します。
+type MigrationsInspector struct {
+	cfg *inspector.Config
+}
+
+// NewMigrationsInspector は接続設定からマイグレーションディレクトリ用のSchemaInspectorを生成します。
+func NewMigrationsInspector(cfg *inspector.Config) (inspector.SchemaInspector, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("DB_PATH must be set to the migrations directory")
+	}
+	return &MigrationsInspector{cfg: cfg}, nil
+}
+
+var (
+	golangMigratePattern = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+	flywayPattern        = regexp.MustCompile(`^V([0-9._]+)__.*\.sql$`)
+	flywayRepeatPattern  = regexp.MustCompile(`^R__(.*)\.sql$`)
+)
+
+// migrationFile は適用対象のマイグレーションファイルです。
+type migrationFile struct {
+	path       string
+	version    []uint64
+	repeatable bool
+	sortKey    string
+}
+
+// Inspect はマイグレーションファイルをバージョン順（Flywayの繰り返し可能マイグレーションは最後）に適用します。
+func (i *MigrationsInspector) Inspect(ctx context.Context) (*sql_model.DB, error) {
+	files, err := listMigrations(i.cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no migration files found in %s", i.cfg.Path)
+	}
+
+	s := &schema{name: i.cfg.Database}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		src, err := os.ReadFile(file.path)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.parse(string(src)); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file.path), err)
+		}
+	}
+
+	if s.name == "" {
+		s.name = filepath.Base(filepath.Clean(i.cfg.Path))
+	}
+
+	return s.toModel(), nil
+}
+
+// listMigrations はディレクトリ内のマイグレーションファイルを適用順に並べて返します。
+func listMigrations(dir string) ([]*migrationFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*migrationFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+
+		var version string
+		switch {
+		case golangMigratePattern.MatchString(name):
+			version = golangMigratePattern.FindStringSubmatch(name)[1]
+		case flywayPattern.MatchString(name):
+			version = flywayPattern.FindStringSubmatch(name)[1]
+		case flywayRepeatPattern.MatchString(name):
+			files = append(files, &migrationFile{
+				path:       path,
+				repeatable: true,
+				sortKey:    flywayRepeatPattern.FindStringSubmatch(name)[1],
+			})
+			continue
+		default:
+			continue
+		}
+
+		parsed, err := parseVersion(version)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		files = append(files, &migrationFile{path: path, version: parsed, sortKey: name})
+	}
+
+	sort.SliceStable(files, func(a, b int) bool {
+		fa, fb := files[a], files[b]
+		if fa.repeatable != fb.repeatable {
+			return !fa.repeatable
+		}
+		if c := compareVersion(fa.version, fb.version); c != 0 {
+			return c < 0
+		}
+		return fa.sortKey < fb.sortKey
+	})
+
+	return files, nil
+}
+
+// parseVersion は「1.2.3」「1_2」「20240101120000」などのバージョン表記を数値の並びに変換します。
+func parseVersion(version string) ([]uint64, error) {
+	var parts []uint64
+	for _, part := range strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == '_' }) {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q", version)
+		}
+		parts = append(parts, n)
+	}
+	return parts, nil
+}
+
+// compareVersion はバージョンを数値として比較します。桁数が異なる場合は不足分を0とみなします。
+func compareVersion(a, b []uint64) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y uint64
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
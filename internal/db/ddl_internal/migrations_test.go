@@ -0,0 +1,140 @@
+package ddl_internal
+
+import (
+	"context"
+	"export-db-info/internal/db/inspector"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// マイグレーションファイルをバージョンの数値順に並べ、対象外のファイルを除くこと
+func TestListMigrations(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want []string
+	}{
+		{
+			dir:  "testdata/migrate",
+			want: []string{"1_create_users.up.sql", "2_create_posts.up.sql", "10_alter_users.up.sql"},
+		},
+		{
+			dir:  "testdata/flyway",
+			want: []string{"V1__init.sql", "V1.1__add_name.sql", "V2__rename_name.sql", "V10__add_email.sql", "R__account_view.sql"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			files, err := listMigrations(tt.dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, file := range files {
+				got = append(got, filepath.Base(file.path))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// バージョンを桁ごとに数値として比較すること
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1", "1", 0},
+		{"1", "1.0", 0},
+		{"1.1", "1", 1},
+		{"2", "10", -1},
+		{"1.10", "1.9", 1},
+		{"1_2", "1.2", 0},
+		{"20240101120000", "20231231235959", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			a, err := parseVersion(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := parseVersion(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := compareVersion(a, b); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// ALTER TABLE・DROP TABLE を順に適用した最終的なスキーマになること
+func TestMigrationsInspect(t *testing.T) {
+	type column struct {
+		name       string
+		typ        string
+		isNullable bool
+	}
+	tests := []struct {
+		dir    string
+		name   string
+		tables map[string][]column
+		views  []string
+	}{
+		{
+			dir:  "testdata/migrate",
+			name: "migrate",
+			tables: map[string][]column{
+				"articles": {{"id", "int", false}, {"user_id", "int", true}},
+				"users":    {{"id", "int", false}, {"email", "varchar(255)", false}, {"display_name", "varchar(100)", false}},
+			},
+		},
+		{
+			dir:  "testdata/flyway",
+			name: "flyway",
+			tables: map[string][]column{
+				"accounts": {{"id", "int", false}, {"full_name", "varchar(100)", false}, {"email", "varchar(255)", true}},
+				"sessions": {{"id", "int", false}},
+			},
+			views: []string{"account_emails"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			i, err := NewMigrationsInspector(&inspector.Config{Path: tt.dir})
+			if err != nil {
+				t.Fatal(err)
+			}
+			db, err := i.Inspect(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if db.Name != tt.name {
+				t.Errorf("got database name %q, want %q", db.Name, tt.name)
+			}
+
+			got := make(map[string][]column)
+			for _, table := range db.Tables {
+				var columns []column
+				for _, col := range table.Columns {
+					columns = append(columns, column{col.Name, col.Type, col.IsNullable})
+				}
+				got[table.Name] = columns
+			}
+			if !reflect.DeepEqual(got, tt.tables) {
+				t.Errorf("got tables %+v, want %+v", got, tt.tables)
+			}
+
+			var views []string
+			for _, view := range db.Views {
+				views = append(views, view.Name)
+			}
+			if !reflect.DeepEqual(views, tt.views) {
+				t.Errorf("got views %q, want %q", views, tt.views)
+			}
+		})
+	}
+}
@@ -0,0 +1,814 @@
+package ddl_internal
+
+import (
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parser はトークン列を先頭から読み進めるための再帰下降パーサです。
+type parser struct {
+	src    string // 解析中のSQL文（ルーチン本体などを元の表記のまま取り出すために保持する）
+	tokens []token
+	pos    int
+}
+
+func newParser(src string, tokens []token) *parser {
+	return &parser{src: src, tokens: tokens}
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.tokens) {
+		return token{kind: tokenEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) atEnd() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// rest は現在位置から文末までを元のSQLの表記（改行・インデントを含む）のまま返し、パーサを文末まで進めます。
+// mysqldump はトリガー等をバージョン付きコメントで囲むため、末尾のコメント終端は取り除きます。
+func (p *parser) rest() string {
+	if p.atEnd() {
+		return ""
+	}
+	text := strings.TrimSpace(p.src[p.peek().pos:])
+	p.pos = len(p.tokens)
+	return strings.TrimSpace(strings.TrimSuffix(text, "*/"))
+}
+
+// accept は指定したキーワードの並びが続く場合にだけそれらを読み進めます。
+func (p *parser) accept(keywords ...string) bool {
+	for i, kw := range keywords {
+		if p.pos+i >= len(p.tokens) || !p.tokens[p.pos+i].is(kw) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+func (p *parser) acceptSymbol(symbol string) bool {
+	if p.peek().isSymbol(symbol) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(keywords ...string) error {
+	if !p.accept(keywords...) {
+		return fmt.Errorf("expected %s but got %q", strings.Join(keywords, " "), p.peek().raw)
+	}
+	return nil
+}
+
+func (p *parser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return fmt.Errorf("expected %q but got %q", symbol, p.peek().raw)
+	}
+	return nil
+}
+
+// parseName は識別子を1つ読み取ります。「データベース名.テーブル名」の形式の場合は最後の名前を返します。
+func (p *parser) parseName() (string, error) {
+	t := p.next()
+	if !t.isName() {
+		return "", fmt.Errorf("expected identifier but got %q", t.raw)
+	}
+	name := t.text
+	for p.peek().isSymbol(".") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].isName() {
+		p.pos++
+		name = p.next().text
+	}
+	return name, nil
+}
+
+// parseParenthesized は括弧で囲まれた部分を読み取り、内側のトークン列を返します。
+func (p *parser) parseParenthesized() ([]token, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	start := p.pos
+	depth := 1
+	for !p.atEnd() {
+		t := p.next()
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")"):
+			depth--
+			if depth == 0 {
+				return p.tokens[start : p.pos-1], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unbalanced parentheses")
+}
+
+// skipElement はテーブル定義の要素の終わり（同じ階層の「,」または「)」）の手前まで読み飛ばします。
+func (p *parser) skipElement() {
+	depth := 0
+	for !p.atEnd() {
+		t := p.peek()
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")"):
+			if depth == 0 {
+				return
+			}
+			depth--
+		case t.isSymbol(","):
+			if depth == 0 {
+				return
+			}
+		}
+		p.pos++
+	}
+}
+
+// atElementEnd はテーブル定義の要素の終わりに達したかを判定します。
+func (p *parser) atElementEnd() bool {
+	t := p.peek()
+	return t.kind == tokenEOF || t.isSymbol(",") || t.isSymbol(")")
+}
+
+// parseKeyParts はインデックスのキー部分 (col(10) DESC, (expr), ...) を読み取り、定義順のカラム一覧を返します。
+func (p *parser) parseKeyParts() ([]*sql_model.IndexColumn, error) {
+	inner, err := p.parseParenthesized()
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []*sql_model.IndexColumn
+	for _, tokens := range splitTopLevel(inner) {
+		if len(tokens) == 0 {
+			continue
+		}
+		part := new(sql_model.IndexColumn)
+		if last := tokens[len(tokens)-1]; last.is("ASC", "DESC") {
+			part.Descending = last.is("DESC")
+			tokens = tokens[:len(tokens)-1]
+		}
+
+		switch {
+		case len(tokens) > 0 && tokens[0].isName():
+			part.Name = tokens[0].text
+			// col(10) のプレフィックス長
+			if len(tokens) == 4 && tokens[1].isSymbol("(") && tokens[2].kind == tokenNumber && tokens[3].isSymbol(")") {
+				part.Length, _ = strconv.ParseInt(tokens[2].text, 10, 64)
+			}
+		case len(tokens) > 2 && tokens[0].isSymbol("(") && tokens[len(tokens)-1].isSymbol(")"):
+			// 関数インデックス ((expr))
+			part.Expression = joinTokens(tokens[1 : len(tokens)-1])
+		default:
+			part.Expression = joinTokens(tokens)
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// parseKeyColumns はキー部分を読み取り、カラム名の一覧を返します（外部キー用）。
+func (p *parser) parseKeyColumns() ([]string, error) {
+	parts, err := p.parseKeyParts()
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	for _, part := range parts {
+		if part.Name != "" {
+			columns = append(columns, part.Name)
+		}
+	}
+	return columns, nil
+}
+
+// splitTopLevel はトークン列を括弧の外側にある「,」で分割します。
+func splitTopLevel(tokens []token) [][]token {
+	var parts [][]token
+	depth, start := 0, 0
+	for i, t := range tokens {
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")"):
+			depth--
+		case t.isSymbol(",") && depth == 0:
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		parts = append(parts, tokens[start:])
+	}
+	return parts
+}
+
+// parseStatement は1つのSQL文を解析してスキーマ定義へ反映します。テーブル定義に関係しない文は無視します。
+func (s *schema) parseStatement(src string, tokens []token) error {
+	p := newParser(src, tokens)
+
+	switch {
+	case p.accept("CREATE"):
+		p.accept("TEMPORARY")
+		switch {
+		case p.accept("TABLE"):
+			return s.parseCreateTable(p)
+		case p.accept("INDEX"):
+			return s.parseCreateIndex(p, &index{})
+		case p.accept("UNIQUE", "INDEX"):
+			return s.parseCreateIndex(p, &index{isUnique: true})
+		case p.accept("FULLTEXT", "INDEX"):
+			return s.parseCreateIndex(p, &index{typ: "FULLTEXT"})
+		case p.accept("SPATIAL", "INDEX"):
+			return s.parseCreateIndex(p, &index{typ: "SPATIAL"})
+		default:
+			return s.parseCreateObject(p)
+		}
+	case p.accept("ALTER"):
+		p.accept("ONLINE")
+		p.accept("IGNORE")
+		if p.accept("TABLE") {
+			return s.parseAlterTable(p)
+		}
+	case p.accept("DROP"):
+		p.accept("TEMPORARY")
+		switch {
+		case p.accept("TABLE"):
+			return s.parseDropTable(p)
+		case p.accept("INDEX"):
+			return s.parseDropIndex(p)
+		case p.accept("VIEW"):
+			return s.parseDropView(p)
+		case p.accept("TRIGGER"):
+			return s.parseDropTrigger(p)
+		case p.accept("PROCEDURE"):
+			return s.parseDropRoutine(p, "PROCEDURE")
+		case p.accept("FUNCTION"):
+			return s.parseDropRoutine(p, "FUNCTION")
+		case p.accept("EVENT"):
+			return s.parseDropEvent(p)
+		}
+	case p.accept("RENAME", "TABLE"):
+		return s.parseRenameTable(p)
+	case p.accept("USE"):
+		name, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		if s.name == "" {
+			s.name = name
+		}
+	}
+
+	return nil
+}
+
+// parseCreateTable は CREATE TABLE 文を解析します。
+// IF NOT EXISTS 付きで同名のテーブルが既にある場合は何もせず、それ以外の場合は置き換えます。
+func (s *schema) parseCreateTable(p *parser) error {
+	ifNotExists := p.accept("IF", "NOT", "EXISTS")
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	if ifNotExists && s.findTable(name) != nil {
+		return nil
+	}
+
+	t := &table{name: name, options: make(map[string]string)}
+
+	switch {
+	case p.accept("LIKE"):
+		if t, err = s.parseLike(p, name); err != nil {
+			return err
+		}
+	case p.peek().isSymbol("(") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].is("LIKE"):
+		p.next()
+		p.next()
+		if t, err = s.parseLike(p, name); err != nil {
+			return err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return err
+		}
+	case p.acceptSymbol("("):
+		for {
+			if err := p.parseTableElement(t); err != nil {
+				return fmt.Errorf("table %s: %w", name, err)
+			}
+			if p.acceptSymbol(",") {
+				continue
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return fmt.Errorf("table %s: %w", name, err)
+			}
+			break
+		}
+		p.parseTableOptions(t)
+	}
+
+	if p.accept("PARTITION", "BY") {
+		if t.partitioning, err = p.parsePartitionBy(); err != nil {
+			return fmt.Errorf("table %s: %w", name, err)
+		}
+	}
+
+	s.replaceTable(t)
+	return nil
+}
+
+// parseLike は CREATE TABLE ... LIKE で指定された元テーブルの定義を複製します。
+func (s *schema) parseLike(p *parser, name string) (*table, error) {
+	srcName, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	src := s.findTable(srcName)
+	if src == nil {
+		return nil, fmt.Errorf("table %s: source table %s of LIKE is not defined", name, srcName)
+	}
+	return src.clone(name), nil
+}
+
+func (s *schema) replaceTable(t *table) {
+	for i, existing := range s.tables {
+		if strings.EqualFold(existing.name, t.name) {
+			s.tables[i] = t
+			return
+		}
+	}
+	s.tables = append(s.tables, t)
+}
+
+// parseTableElement はカラム定義またはテーブル制約を1つ解析します。
+func (p *parser) parseTableElement(t *table) error {
+	var constraintName string
+	if p.accept("CONSTRAINT") && !p.peek().is("PRIMARY", "UNIQUE", "FOREIGN", "CHECK") {
+		name, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		constraintName = name
+	}
+
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		idx, err := p.parseIndex()
+		if err != nil {
+			return err
+		}
+		idx.name = "PRIMARY"
+		idx.isUnique = true
+		t.primaryKey = idx
+	case p.accept("UNIQUE"):
+		idx, err := p.parseIndex()
+		if err != nil {
+			return err
+		}
+		idx.isUnique = true
+		if idx.name == "" {
+			idx.name = constraintName
+		}
+		t.addIndex(idx)
+	case p.peek().is("INDEX", "KEY"):
+		idx, err := p.parseIndex()
+		if err != nil {
+			return err
+		}
+		t.addIndex(idx)
+	case p.peek().is("FULLTEXT", "SPATIAL"):
+		typ := strings.ToUpper(p.next().text)
+		idx, err := p.parseIndex()
+		if err != nil {
+			return err
+		}
+		idx.typ = typ
+		t.addIndex(idx)
+	case p.accept("FOREIGN", "KEY"):
+		fk, err := p.parseForeignKey()
+		if err != nil {
+			return err
+		}
+		if constraintName != "" {
+			fk.name = constraintName
+		}
+		t.addForeignKey(fk)
+	case p.accept("CHECK"):
+		return p.parseCheck(t, constraintName)
+	default:
+		col, err := p.parseColumn(t)
+		if err != nil {
+			return err
+		}
+		t.columns = append(t.columns, col)
+	}
+
+	return nil
+}
+
+// parseIndex は INDEX / KEY 以降のインデックス定義を解析します。
+func (p *parser) parseIndex() (*index, error) {
+	idx := new(index)
+	_ = p.accept("INDEX") || p.accept("KEY")
+	if !p.peek().isSymbol("(") && !p.peek().is("USING") {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		idx.name = name
+	}
+	if p.accept("USING") {
+		idx.typ = strings.ToUpper(p.next().text)
+	}
+	parts, err := p.parseKeyParts()
+	if err != nil {
+		return nil, err
+	}
+	idx.parts = parts
+	p.parseIndexOptions(idx)
+
+	return idx, nil
+}
+
+// parseIndexOptions はキー部分に続く USING / COMMENT / VISIBLE などのインデックスオプションを読み取ります。
+func (p *parser) parseIndexOptions(idx *index) {
+	for !p.atElementEnd() {
+		switch {
+		case p.accept("USING"):
+			idx.typ = strings.ToUpper(p.next().text)
+		case p.accept("COMMENT"):
+			idx.comment = p.next().text
+		case p.accept("INVISIBLE"):
+			idx.invisible = true
+		case p.accept("VISIBLE"):
+			idx.invisible = false
+		default:
+			p.next()
+		}
+	}
+}
+
+// parseForeignKey は FOREIGN KEY 以降の外部キー定義を解析します。
+func (p *parser) parseForeignKey() (*foreignKey, error) {
+	fk := new(foreignKey)
+	if !p.peek().isSymbol("(") {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		fk.name = name
+	}
+	columns, err := p.parseKeyColumns()
+	if err != nil {
+		return nil, err
+	}
+	fk.columns = columns
+
+	if err := p.expect("REFERENCES"); err != nil {
+		return nil, err
+	}
+	if fk.refTable, err = p.parseName(); err != nil {
+		return nil, err
+	}
+	if fk.refColumns, err = p.parseKeyColumns(); err != nil {
+		return nil, err
+	}
+	for !p.atElementEnd() {
+		switch {
+		case p.accept("ON", "DELETE"):
+			fk.onDelete = p.parseReferentialAction()
+		case p.accept("ON", "UPDATE"):
+			fk.onUpdate = p.parseReferentialAction()
+		default:
+			p.next()
+		}
+	}
+
+	return fk, nil
+}
+
+// parseReferentialAction は ON DELETE / ON UPDATE に続く参照アクションを読み取ります。
+func (p *parser) parseReferentialAction() string {
+	switch {
+	case p.accept("SET", "NULL"):
+		return "SET NULL"
+	case p.accept("SET", "DEFAULT"):
+		return "SET DEFAULT"
+	case p.accept("NO", "ACTION"):
+		return "NO ACTION"
+	default:
+		return strings.ToUpper(p.next().text)
+	}
+}
+
+// parseColumn はカラム定義を解析します。カラム定義内の PRIMARY KEY / UNIQUE はテーブルの制約として追加します。
+// ALTER TABLE で使われる FIRST / AFTER の手前で解析を終えます。
+func (p *parser) parseColumn(t *table) (*sql_model.Column, error) {
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	col := &sql_model.Column{Name: name, IsNullable: true}
+	if err := p.parseColumnType(col); err != nil {
+		return nil, fmt.Errorf("column %s: %w", name, err)
+	}
+
+	var autoIncrement, invisible bool
+	var onUpdate, constraintName string
+	for !p.atElementEnd() && !p.peek().is("FIRST", "AFTER") {
+		switch {
+		case p.accept("NOT", "NULL"):
+			col.IsNullable = false
+		case p.accept("NULL"):
+			col.IsNullable = true
+		case p.accept("DEFAULT"):
+			if col.Default, col.IsDefaultExpression, err = p.parseDefault(); err != nil {
+				return nil, fmt.Errorf("column %s: %w", name, err)
+			}
+		case p.accept("ON", "UPDATE"):
+			value, _, err := p.parseDefault()
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", name, err)
+			}
+			if value != nil {
+				onUpdate = *value
+			}
+		case p.accept("AUTO_INCREMENT"):
+			autoIncrement = true
+		case p.accept("INVISIBLE"):
+			invisible = true
+		case p.accept("COMMENT"):
+			col.Comment = p.next().text
+		case p.accept("PRIMARY", "KEY"), p.accept("KEY"):
+			t.primaryKey = &index{name: "PRIMARY", parts: []*sql_model.IndexColumn{{Name: name}}, isUnique: true}
+			col.IsNullable = false
+		case p.accept("UNIQUE"):
+			p.accept("KEY")
+			t.addIndex(&index{parts: []*sql_model.IndexColumn{{Name: name}}, isUnique: true})
+		case p.accept("GENERATED", "ALWAYS"), p.accept("AS"):
+			p.accept("AS")
+			expression, err := p.parseParenthesized()
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", name, err)
+			}
+			col.Expression = joinTokens(expression)
+			col.Generated = "VIRTUAL"
+		case p.accept("VIRTUAL"):
+			col.Generated = "VIRTUAL"
+		case p.accept("STORED"), p.accept("PERSISTENT"):
+			col.Generated = "STORED"
+		case p.accept("CONSTRAINT"):
+			if constraintName, err = p.parseName(); err != nil {
+				return nil, fmt.Errorf("column %s: %w", name, err)
+			}
+		case p.accept("CHECK"):
+			// MySQLはカラム定義内のCHECK制約もテーブルのCHECK制約として扱う
+			if err := p.parseCheck(t, constraintName); err != nil {
+				return nil, fmt.Errorf("column %s: %w", name, err)
+			}
+			constraintName = ""
+		case p.accept("REFERENCES"):
+			// MySQLはカラム定義内のREFERENCES句を解析するだけで制約として扱わないため読み飛ばす
+			p.skipElement()
+		case p.accept("COLLATE"):
+			col.Collation = p.next().text
+		case p.accept("CHARACTER", "SET"), p.accept("CHARSET"):
+			col.CharacterSet = p.next().text
+		case p.accept("COLUMN_FORMAT"), p.accept("STORAGE"), p.accept("SRID"):
+			p.next()
+		case p.accept("ENGINE_ATTRIBUTE"), p.accept("SECONDARY_ENGINE_ATTRIBUTE"):
+			p.acceptSymbol("=")
+			p.next()
+		default:
+			// VISIBLE / ENFORCED などの属性
+			p.next()
+		}
+	}
+
+	// information_schema.COLUMNS.EXTRA と同じ形式にまとめる
+	var extra []string
+	if autoIncrement {
+		extra = append(extra, "auto_increment")
+	}
+	if onUpdate != "" {
+		extra = append(extra, "on update "+onUpdate)
+	}
+	if col.Generated != "" {
+		extra = append(extra, col.Generated+" GENERATED")
+	}
+	if invisible {
+		extra = append(extra, "INVISIBLE")
+	}
+	col.Extra = strings.Join(extra, " ")
+	setDefault(col, col.Default, col.IsDefaultExpression)
+
+	return col, nil
+}
+
+// typeAliases はMySQLがinformation_schema上で別名に置き換えるデータ型です。
+var typeAliases = map[string]string{
+	"integer": "int",
+	"bool":    "tinyint(1)",
+	"boolean": "tinyint(1)",
+	"dec":     "decimal",
+	"numeric": "decimal",
+	"fixed":   "decimal",
+	"real":    "double",
+}
+
+// parseDataType はデータ型を読み取り、information_schema.COLUMNS.COLUMN_TYPE と同じ形式の文字列で返します。
+func (p *parser) parseDataType() (string, error) {
+	col := new(sql_model.Column)
+	err := p.parseColumnType(col)
+	return col.Type, err
+}
+
+// parseColumnType はデータ型を読み取り、カラムのデータ型と、型に続けて指定された文字セット・照合順序、
+// ENUM / SET 型の値の一覧を設定します。
+func (p *parser) parseColumnType(col *sql_model.Column) error {
+	t := p.next()
+	if t.kind != tokenIdent {
+		return fmt.Errorf("expected data type but got %q", t.raw)
+	}
+	dataType := strings.ToLower(t.text)
+
+	// 複数語からなる型名
+	switch {
+	case dataType == "double":
+		p.accept("PRECISION")
+	case dataType == "national":
+		dataType = strings.ToLower(p.next().text)
+	case dataType == "long" && p.peek().is("VARCHAR", "VARBINARY"):
+		dataType = "medium" + strings.ToLower(p.next().text)
+	}
+	if p.accept("VARYING") {
+		dataType = "var" + dataType
+	}
+	if alias, ok := typeAliases[dataType]; ok {
+		dataType = alias
+	}
+
+	if p.peek().isSymbol("(") {
+		args, err := p.parseParenthesized()
+		if err != nil {
+			return err
+		}
+		var raw []string
+		for _, arg := range args {
+			raw = append(raw, arg.raw)
+			if (dataType == "enum" || dataType == "set") && arg.kind == tokenString {
+				col.EnumValues = append(col.EnumValues, arg.text)
+			}
+		}
+		dataType += "(" + strings.Join(raw, "") + ")"
+	}
+
+	for {
+		switch {
+		case p.accept("UNSIGNED"):
+			dataType += " unsigned"
+		case p.accept("ZEROFILL"):
+			dataType += " zerofill"
+		case p.accept("SIGNED"), p.accept("BINARY"), p.accept("ASCII"), p.accept("UNICODE"):
+		case p.accept("CHARACTER", "SET"), p.accept("CHARSET"):
+			col.CharacterSet = p.next().text
+		case p.accept("COLLATE"):
+			col.Collation = p.next().text
+		default:
+			col.Type = dataType
+			return nil
+		}
+	}
+}
+
+// parseDefault は DEFAULT 句の値を読み取り、値と式かどうかを返します。DEFAULT NULL の場合はnilを返します。
+// 文字列リテラルは引用符を外し、式はそのまま返します。
+func (p *parser) parseDefault() (*string, bool, error) {
+	t := p.peek()
+	var value string
+	isExpression := false
+	switch {
+	case t.isSymbol("("):
+		// MySQL 8.0.13以降の式デフォルト
+		inner, err := p.parseParenthesized()
+		if err != nil {
+			return nil, false, err
+		}
+		value, isExpression = joinTokens(inner), true
+	case t.isSymbol("-") || t.isSymbol("+"):
+		p.next()
+		value = t.text + p.next().text
+	case t.kind == tokenIdent && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].kind == tokenString:
+		// 文字セット指定（_utf8mb4'abc'）やビット・16進リテラル（b'1' / x'1F'）
+		p.next()
+		literal := p.next()
+		value = t.raw + literal.raw
+		if strings.HasPrefix(t.text, "_") {
+			value = literal.text
+		}
+	case t.is("CURRENT_TIMESTAMP", "NOW", "LOCALTIME", "LOCALTIMESTAMP"):
+		p.next()
+		value, isExpression = "CURRENT_TIMESTAMP", true
+		if p.peek().isSymbol("(") {
+			inner, err := p.parseParenthesized()
+			if err != nil {
+				return nil, false, err
+			}
+			if len(inner) > 0 {
+				value += "(" + joinTokens(inner) + ")"
+			}
+		}
+	case t.is("NULL"):
+		p.next()
+		return nil, false, nil
+	default:
+		p.next()
+		value = t.text
+	}
+	return &value, isExpression, nil
+}
+
+// setDefault はカラムのデフォルト値を設定し、EXTRA の DEFAULT_GENERATED（式デフォルトの印）を合わせて更新します。
+func setDefault(col *sql_model.Column, value *string, isExpression bool) {
+	col.Default, col.IsDefaultExpression = value, isExpression
+	extra := strings.TrimSpace(strings.TrimPrefix(col.Extra, "DEFAULT_GENERATED"))
+	if isExpression {
+		extra = strings.TrimSpace("DEFAULT_GENERATED " + extra)
+	}
+	col.Extra = extra
+}
+
+// parseTableOptions は ENGINE=InnoDB COMMENT='...' などのテーブルオプションを読み取ります。
+// パーティション定義や AS SELECT 以降は対象外とします。
+func (p *parser) parseTableOptions(t *table) {
+	for !p.atEnd() {
+		if p.peek().is("PARTITION", "AS", "SELECT", "IGNORE", "REPLACE") {
+			return
+		}
+		if p.acceptSymbol(",") {
+			continue
+		}
+		p.parseTableOption(t)
+	}
+}
+
+// parseTableOption はテーブルオプションを1つ読み取ります。
+func (p *parser) parseTableOption(t *table) {
+	p.accept("DEFAULT")
+
+	var key string
+	switch {
+	case p.accept("CHARACTER", "SET"), p.accept("CHARSET"):
+		key = "CHARSET"
+	default:
+		key = strings.ToUpper(p.next().text)
+	}
+	p.acceptSymbol("=")
+	if p.peek().isSymbol("(") {
+		// UNION=(t1, t2) などの括弧付きの値は保持しない
+		p.parseParenthesized()
+		return
+	}
+	t.options[key] = p.next().text
+}
+
+// clone は別名でテーブル定義を複製します（CREATE TABLE ... LIKE 用）。
+func (t *table) clone(name string) *table {
+	c := &table{
+		name:    name,
+		options: make(map[string]string),
+	}
+	if t.primaryKey != nil {
+		c.primaryKey = t.primaryKey.clone()
+	}
+	for _, col := range t.columns {
+		copied := *col
+		c.columns = append(c.columns, &copied)
+	}
+	for _, idx := range t.indexes {
+		c.indexes = append(c.indexes, idx.clone())
+	}
+	for key, value := range t.options {
+		c.options[key] = value
+	}
+	c.partitioning = clonePartitioning(t.partitioning)
+	c.checks = cloneChecks(t.checks)
+	c.renameChecks(t.name, name)
+	// MySQLの CREATE TABLE ... LIKE は外部キー制約を複製しない
+	return c
+}
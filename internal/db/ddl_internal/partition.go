@@ -0,0 +1,300 @@
+package ddl_internal
+
+import (
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePartitionBy は PARTITION BY 以降のパーティション定義を解析します。
+// パーティションの定義を省略して PARTITIONS n のみを指定した場合は、MySQLと同様に p0, p1, ... と名前を付けます。
+func (p *parser) parsePartitionBy() (*sql_model.Partitioning, error) {
+	partitioning := &sql_model.Partitioning{}
+
+	var err error
+	if partitioning.Method, partitioning.Expression, err = p.parsePartitionMethod(); err != nil {
+		return nil, err
+	}
+	count, subCount := 0, 0
+	if p.accept("PARTITIONS") {
+		count, _ = strconv.Atoi(p.next().text)
+	}
+	if p.accept("SUBPARTITION", "BY") {
+		if partitioning.SubpartitionMethod, partitioning.SubpartitionExpression, err = p.parsePartitionMethod(); err != nil {
+			return nil, err
+		}
+		if p.accept("SUBPARTITIONS") {
+			subCount, _ = strconv.Atoi(p.next().text)
+		}
+	}
+
+	if p.peek().isSymbol("(") {
+		if partitioning.Partitions, err = p.parsePartitionDefinitions(); err != nil {
+			return nil, err
+		}
+	} else {
+		partitioning.Partitions = numberedPartitions("p", 0, count)
+	}
+
+	for _, partition := range partitioning.Partitions {
+		if len(partition.Subpartitions) == 0 && subCount > 0 {
+			partition.Subpartitions = numberedPartitions(partition.Name+"sp", 0, subCount)
+		}
+	}
+	return partitioning, nil
+}
+
+// parsePartitionMethod は [LINEAR] HASH(expr) / [LINEAR] KEY(cols) / RANGE [COLUMNS](...) / LIST [COLUMNS](...) を読み取ります。
+func (p *parser) parsePartitionMethod() (string, string, error) {
+	linear := p.accept("LINEAR")
+
+	var method string
+	switch {
+	case p.accept("HASH"):
+		method = "HASH"
+	case p.accept("KEY"):
+		method = "KEY"
+		if p.accept("ALGORITHM") {
+			p.acceptSymbol("=")
+			p.next()
+		}
+	case p.accept("RANGE"):
+		method = "RANGE"
+	case p.accept("LIST"):
+		method = "LIST"
+	default:
+		return "", "", fmt.Errorf("unknown partition method %q", p.peek().raw)
+	}
+	if p.accept("COLUMNS") {
+		method += " COLUMNS"
+	}
+	if linear {
+		method = "LINEAR " + method
+	}
+
+	expression, err := p.parseParenthesized()
+	if err != nil {
+		return "", "", err
+	}
+	return method, joinTokens(expression), nil
+}
+
+// parsePartitionDefinitions は (PARTITION p0 VALUES LESS THAN (...) ..., ...) のパーティション定義の一覧を読み取ります。
+func (p *parser) parsePartitionDefinitions() ([]*sql_model.Partition, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+
+	var partitions []*sql_model.Partition
+	for {
+		partition, err := p.parsePartitionDefinition("PARTITION")
+		if err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, partition)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	return partitions, p.expectSymbol(")")
+}
+
+// parsePartitionDefinition はパーティション（keyword が SUBPARTITION の場合はサブパーティション）の定義を1つ読み取ります。
+// ENGINE / DATA DIRECTORY などの物理的なオプションは保持しません。
+func (p *parser) parsePartitionDefinition(keyword string) (*sql_model.Partition, error) {
+	if err := p.expect(keyword); err != nil {
+		return nil, err
+	}
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	partition := &sql_model.Partition{Name: name}
+
+	switch {
+	case p.accept("VALUES", "LESS", "THAN"):
+		if p.accept("MAXVALUE") {
+			partition.Bound = "LESS THAN MAXVALUE"
+			break
+		}
+		values, err := p.parseParenthesized()
+		if err != nil {
+			return nil, err
+		}
+		partition.Bound = "LESS THAN (" + joinTokens(values) + ")"
+	case p.accept("VALUES", "IN"):
+		values, err := p.parseParenthesized()
+		if err != nil {
+			return nil, err
+		}
+		partition.Bound = "IN (" + joinTokens(values) + ")"
+	}
+
+	for !p.atElementEnd() {
+		switch {
+		case p.accept("COMMENT"):
+			p.acceptSymbol("=")
+			partition.Comment = p.next().text
+		case keyword == "PARTITION" && p.acceptSymbol("("):
+			for {
+				subpartition, err := p.parsePartitionDefinition("SUBPARTITION")
+				if err != nil {
+					return nil, err
+				}
+				partition.Subpartitions = append(partition.Subpartitions, subpartition)
+				if !p.acceptSymbol(",") {
+					break
+				}
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+		default:
+			p.next()
+		}
+	}
+	return partition, nil
+}
+
+// numberedPartitions は「接頭辞+連番」の名前のパーティションを作成します。
+func numberedPartitions(prefix string, start, count int) []*sql_model.Partition {
+	var partitions []*sql_model.Partition
+	for i := start; i < start+count; i++ {
+		partitions = append(partitions, &sql_model.Partition{Name: prefix + strconv.Itoa(i)})
+	}
+	return partitions
+}
+
+// parseAlterPartition は ALTER TABLE のパーティション操作（ADD / DROP / COALESCE / REORGANIZE PARTITION と
+// REMOVE PARTITIONING）をテーブルのパーティション構成へ反映します。
+func (p *parser) parseAlterPartition(t *table, operation string) error {
+	if operation == "REMOVE" {
+		t.partitioning = nil
+		return p.expect("PARTITIONING")
+	}
+	if err := p.expect("PARTITION"); err != nil {
+		return err
+	}
+	if t.partitioning == nil {
+		return fmt.Errorf("%s partition: table %s is not partitioned", strings.ToLower(operation), t.name)
+	}
+
+	switch operation {
+	case "ADD":
+		// HASH / KEY の場合は PARTITIONS n で追加する数のみを指定できる
+		if p.accept("PARTITIONS") {
+			n, _ := strconv.Atoi(p.next().text)
+			t.partitioning.Partitions = append(t.partitioning.Partitions,
+				numberedPartitions("p", len(t.partitioning.Partitions), n)...)
+			return nil
+		}
+		partitions, err := p.parsePartitionDefinitions()
+		if err != nil {
+			return err
+		}
+		t.partitioning.Partitions = append(t.partitioning.Partitions, partitions...)
+	case "DROP":
+		names, err := p.parsePartitionNames()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			t.dropPartition(name)
+		}
+	case "COALESCE":
+		n, _ := strconv.Atoi(p.next().text)
+		if remain := len(t.partitioning.Partitions) - n; remain >= 0 {
+			t.partitioning.Partitions = t.partitioning.Partitions[:remain]
+		}
+	case "REORGANIZE":
+		names, err := p.parsePartitionNames()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("INTO"); err != nil {
+			return err
+		}
+		partitions, err := p.parsePartitionDefinitions()
+		if err != nil {
+			return err
+		}
+		// 再編成後のパーティションは、元のパーティションのうち先頭のものの位置へ置き換える
+		position := -1
+		var kept []*sql_model.Partition
+		for _, partition := range t.partitioning.Partitions {
+			if containsFold(names, partition.Name) {
+				if position < 0 {
+					position = len(kept)
+				}
+				continue
+			}
+			kept = append(kept, partition)
+		}
+		if position < 0 {
+			position = len(kept)
+		}
+		t.partitioning.Partitions = append(kept[:position], append(partitions, kept[position:]...)...)
+	}
+	return nil
+}
+
+// parsePartitionNames はカンマ区切りのパーティション名を読み取ります。
+// ALTER TABLE の変更内容の区切りと区別するため、続く名前がキーワードの場合は読み進めません。
+func (p *parser) parsePartitionNames() ([]string, error) {
+	var names []string
+	for {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.peek().isSymbol(",") || p.pos+1 >= len(p.tokens) || p.tokens[p.pos+1].is(alterSpecKeywords...) {
+			return names, nil
+		}
+		p.next()
+	}
+}
+
+// alterSpecKeywords は ALTER TABLE の変更内容の先頭となるキーワードです。
+var alterSpecKeywords = []string{"ADD", "DROP", "MODIFY", "CHANGE", "RENAME", "ALTER", "ALGORITHM", "LOCK",
+	"ENGINE", "COMMENT", "COALESCE", "REORGANIZE", "REMOVE", "TRUNCATE", "FORCE", "ORDER", "CONVERT"}
+
+// dropPartition は指定した名前のパーティションを取り除きます。
+func (t *table) dropPartition(name string) {
+	for i, partition := range t.partitioning.Partitions {
+		if strings.EqualFold(partition.Name, name) {
+			t.partitioning.Partitions = append(t.partitioning.Partitions[:i], t.partitioning.Partitions[i+1:]...)
+			return
+		}
+	}
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// clonePartitioning はパーティション構成を複製します（CREATE TABLE ... LIKE 用）。
+func clonePartitioning(src *sql_model.Partitioning) *sql_model.Partitioning {
+	if src == nil {
+		return nil
+	}
+	c := *src
+	c.Partitions = clonePartitions(src.Partitions)
+	return &c
+}
+
+func clonePartitions(src []*sql_model.Partition) []*sql_model.Partition {
+	var partitions []*sql_model.Partition
+	for _, partition := range src {
+		copied := *partition
+		copied.Subpartitions = clonePartitions(partition.Subpartitions)
+		partitions = append(partitions, &copied)
+	}
+	return partitions
+}
@@ -0,0 +1,330 @@
+package ddl_internal
+
+import (
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parseCreateTrigger は TRIGGER 以降のトリガー定義を解析し、対象テーブルへ追加します。
+// FOLLOWS / PRECEDES が指定された場合は、指定されたトリガーの前後に実行順を合わせます。
+func (s *schema) parseCreateTrigger(p *parser, definer string) error {
+	ifNotExists := p.accept("IF", "NOT", "EXISTS")
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+
+	trigger := &sql_model.Trigger{Name: name, Definer: definer}
+	trigger.Timing = strings.ToUpper(p.next().text)
+	trigger.Event = strings.ToUpper(p.next().text)
+	if err := p.expect("ON"); err != nil {
+		return err
+	}
+	tableName, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	t := s.findTable(tableName)
+	if t == nil {
+		return fmt.Errorf("create trigger %s: table %s is not defined", name, tableName)
+	}
+	if err := p.expect("FOR", "EACH", "ROW"); err != nil {
+		return err
+	}
+
+	if ifNotExists && s.findTrigger(name) != nil {
+		return nil
+	}
+	s.dropTrigger(name)
+
+	position := len(t.triggers)
+	if p.peek().is("FOLLOWS", "PRECEDES") {
+		follows := p.next().is("FOLLOWS")
+		other, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		for i, existing := range t.triggers {
+			if strings.EqualFold(existing.Name, other) {
+				position = i
+				if follows {
+					position++
+				}
+				break
+			}
+		}
+	}
+	trigger.Statement = p.rest()
+
+	t.triggers = append(t.triggers[:position], append([]*sql_model.Trigger{trigger}, t.triggers[position:]...)...)
+	return nil
+}
+
+// parseDropTrigger は DROP TRIGGER [IF EXISTS] 文を解析します。
+func (s *schema) parseDropTrigger(p *parser) error {
+	p.accept("IF", "EXISTS")
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	s.dropTrigger(name)
+	return nil
+}
+
+func (s *schema) findTrigger(name string) *sql_model.Trigger {
+	for _, t := range s.tables {
+		for _, trigger := range t.triggers {
+			if strings.EqualFold(trigger.Name, name) {
+				return trigger
+			}
+		}
+	}
+	return nil
+}
+
+func (s *schema) dropTrigger(name string) {
+	for _, t := range s.tables {
+		for i, trigger := range t.triggers {
+			if strings.EqualFold(trigger.Name, name) {
+				t.triggers = append(t.triggers[:i], t.triggers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// parseCreateRoutine は PROCEDURE / FUNCTION 以降のルーチン定義を解析します。
+// 引数リストを持たない CREATE FUNCTION ... SONAME（ユーザー定義関数）は対象外とします。
+func (s *schema) parseCreateRoutine(p *parser, routineType, definer string) error {
+	ifNotExists := p.accept("IF", "NOT", "EXISTS")
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	if !p.peek().isSymbol("(") {
+		return nil
+	}
+
+	routine := &sql_model.Routine{
+		Name:         name,
+		Type:         routineType,
+		Language:     "SQL",
+		SecurityType: "DEFINER",
+		DataAccess:   "CONTAINS SQL",
+		Definer:      definer,
+	}
+
+	// 引数
+	params, err := p.parseParenthesized()
+	if err != nil {
+		return err
+	}
+	for _, item := range splitTopLevel(params) {
+		param, err := parseParameter(p.src, item, routineType)
+		if err != nil {
+			return fmt.Errorf("routine %s: %w", name, err)
+		}
+		routine.Parameters = append(routine.Parameters, param)
+	}
+
+	if routineType == "FUNCTION" {
+		if err := p.expect("RETURNS"); err != nil {
+			return err
+		}
+		if routine.ReturnType, err = p.parseDataType(); err != nil {
+			return err
+		}
+	}
+
+	// 特性（characteristic）の後ろが本体となる
+	for !p.atEnd() {
+		switch {
+		case p.accept("COMMENT"):
+			routine.Comment = p.next().text
+		case p.accept("LANGUAGE"):
+			routine.Language = strings.ToUpper(p.next().text)
+		case p.accept("NOT", "DETERMINISTIC"):
+			routine.IsDeterministic = false
+		case p.accept("DETERMINISTIC"):
+			routine.IsDeterministic = true
+		case p.accept("CONTAINS", "SQL"):
+			routine.DataAccess = "CONTAINS SQL"
+		case p.accept("NO", "SQL"):
+			routine.DataAccess = "NO SQL"
+		case p.accept("READS", "SQL", "DATA"):
+			routine.DataAccess = "READS SQL DATA"
+		case p.accept("MODIFIES", "SQL", "DATA"):
+			routine.DataAccess = "MODIFIES SQL DATA"
+		case p.accept("SQL", "SECURITY"):
+			routine.SecurityType = strings.ToUpper(p.next().text)
+		default:
+			routine.Definition = p.rest()
+		}
+	}
+
+	if ifNotExists && s.findRoutine(routineType, name) >= 0 {
+		return nil
+	}
+	s.dropRoutine(routineType, name)
+	s.routines = append(s.routines, routine)
+	return nil
+}
+
+// parseParameter はルーチンの引数1つ（[IN | OUT | INOUT] 名前 型）を解析します。FUNCTION の引数は常に IN となります。
+func parseParameter(src string, tokens []token, routineType string) (*sql_model.Parameter, error) {
+	p := newParser(src, tokens)
+	param := &sql_model.Parameter{Mode: "IN"}
+	if routineType == "PROCEDURE" && p.peek().is("IN", "OUT", "INOUT") {
+		param.Mode = strings.ToUpper(p.next().text)
+	}
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	param.Name = name
+	if param.DataType, err = p.parseDataType(); err != nil {
+		return nil, err
+	}
+	return param, nil
+}
+
+// parseDropRoutine は DROP PROCEDURE / DROP FUNCTION 文を解析します。
+func (s *schema) parseDropRoutine(p *parser, routineType string) error {
+	p.accept("IF", "EXISTS")
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	s.dropRoutine(routineType, name)
+	return nil
+}
+
+// findRoutine は種類と名前が一致するルーチンの位置を返します。見つからない場合は-1を返します。
+// MySQLのルーチン名は大文字小文字を区別しません。
+func (s *schema) findRoutine(routineType, name string) int {
+	for i, r := range s.routines {
+		if r.Type == routineType && strings.EqualFold(r.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *schema) dropRoutine(routineType, name string) {
+	if i := s.findRoutine(routineType, name); i >= 0 {
+		s.routines = append(s.routines[:i], s.routines[i+1:]...)
+	}
+}
+
+// eventClauseKeywords はイベント定義で日時の式の終わりを示すキーワードです。
+var eventClauseKeywords = []string{"STARTS", "ENDS", "ON", "ENABLE", "DISABLE", "COMMENT", "DO"}
+
+// parseCreateEvent は EVENT 以降のイベント定義を解析します。
+func (s *schema) parseCreateEvent(p *parser, definer string) error {
+	ifNotExists := p.accept("IF", "NOT", "EXISTS")
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("ON", "SCHEDULE"); err != nil {
+		return err
+	}
+
+	event := &sql_model.Event{
+		Name:         name,
+		Status:       "ENABLED",
+		OnCompletion: "NOT PRESERVE",
+		Definer:      definer,
+	}
+	switch {
+	case p.accept("AT"):
+		event.Type = "ONE TIME"
+		event.ExecuteAt = p.parseEventTime()
+	case p.accept("EVERY"):
+		event.Type = "RECURRING"
+		event.IntervalValue = p.next().text
+		event.IntervalField = strings.ToUpper(p.next().text)
+		if p.accept("STARTS") {
+			event.Starts = p.parseEventTime()
+		}
+		if p.accept("ENDS") {
+			event.Ends = p.parseEventTime()
+		}
+	default:
+		return fmt.Errorf("event %s: expected AT or EVERY but got %q", name, p.peek().raw)
+	}
+
+	for !p.atEnd() {
+		switch {
+		case p.accept("ON", "COMPLETION", "NOT", "PRESERVE"):
+			event.OnCompletion = "NOT PRESERVE"
+		case p.accept("ON", "COMPLETION", "PRESERVE"):
+			event.OnCompletion = "PRESERVE"
+		case p.accept("ENABLE"):
+			event.Status = "ENABLED"
+		case p.accept("DISABLE", "ON", "SLAVE"), p.accept("DISABLE", "ON", "REPLICA"):
+			event.Status = "SLAVESIDE_DISABLED"
+		case p.accept("DISABLE"):
+			event.Status = "DISABLED"
+		case p.accept("COMMENT"):
+			event.Comment = p.next().text
+		case p.accept("DO"):
+			event.Definition = p.rest()
+		default:
+			return fmt.Errorf("event %s: unexpected %q", name, p.peek().raw)
+		}
+	}
+
+	if ifNotExists && s.findEvent(name) >= 0 {
+		return nil
+	}
+	s.dropEvent(name)
+	s.events = append(s.events, event)
+	return nil
+}
+
+// parseEventTime はイベントの実行日時を読み取ります。
+// mysqldump が出力する日時の文字列リテラルのみ解釈し、CURRENT_TIMESTAMP + INTERVAL などの式は読み飛ばしてnilを返します。
+func (p *parser) parseEventTime() *time.Time {
+	start := p.pos
+	for !p.atEnd() && !p.peek().is(eventClauseKeywords...) {
+		p.next()
+	}
+	if p.pos-start != 1 || p.tokens[start].kind != tokenString {
+		return nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, p.tokens[start].text); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// parseDropEvent は DROP EVENT [IF EXISTS] 文を解析します。
+func (s *schema) parseDropEvent(p *parser) error {
+	p.accept("IF", "EXISTS")
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+	s.dropEvent(name)
+	return nil
+}
+
+func (s *schema) findEvent(name string) int {
+	for i, e := range s.events {
+		if strings.EqualFold(e.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *schema) dropEvent(name string) {
+	if i := s.findEvent(name); i >= 0 {
+		s.events = append(s.events[:i], s.events[i+1:]...)
+	}
+}
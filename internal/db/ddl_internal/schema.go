@@ -0,0 +1,289 @@
+package ddl_internal
+
+import (
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schema はDDLを解析して組み立てたデータベース定義を保持します。
+type schema struct {
+	name     string
+	tables   []*table
+	views    []*view
+	routines []*sql_model.Routine
+	events   []*sql_model.Event
+}
+
+// table はDDLから読み取ったテーブル定義です。
+// インデックスや外部キーはカラムのフラグへ変換する前の制約単位で保持します。
+type table struct {
+	name         string
+	columns      []*sql_model.Column
+	primaryKey   *index // 主キー（未定義の場合はnil）
+	indexes      []*index
+	foreignKeys  []*foreignKey
+	checks       []*sql_model.Check
+	triggers     []*sql_model.Trigger    // 実行順（実行順は同じタイミング・イベントのトリガー内で数える）
+	partitioning *sql_model.Partitioning // パーティション構成（パーティション化されていない場合はnil）
+	options      map[string]string       // ENGINE / COMMENT などのテーブルオプション（キーは大文字）
+}
+
+// index はインデックス（UNIQUE / FULLTEXT / SPATIAL を含む）の定義です。
+type index struct {
+	name      string
+	parts     []*sql_model.IndexColumn
+	isUnique  bool
+	typ       string // USING / FULLTEXT / SPATIAL で指定された種類（省略時は空）
+	comment   string
+	invisible bool
+}
+
+// foreignKey は外部キー制約の定義です。
+type foreignKey struct {
+	name       string
+	columns    []string
+	refTable   string
+	refColumns []string
+	onUpdate   string // 省略時は空
+	onDelete   string // 省略時は空
+}
+
+func (s *schema) findTable(name string) *table {
+	for _, t := range s.tables {
+		if strings.EqualFold(t.name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (t *table) findColumn(name string) *sql_model.Column {
+	return findColumn(t.columns, name)
+}
+
+// addIndex はインデックスを追加します。名前が省略された場合はMySQLと同様に先頭カラム名から名前を付けます。
+func (t *table) addIndex(idx *index) {
+	if idx.name == "" && len(idx.parts) > 0 {
+		base := idx.parts[0].Name
+		if base == "" {
+			base = "functional_index"
+		}
+		idx.name = base
+		for n := 2; t.findIndex(idx.name) != nil; n++ {
+			idx.name = fmt.Sprintf("%s_%d", base, n)
+		}
+	}
+	t.indexes = append(t.indexes, idx)
+}
+
+// allIndexes は主キーを先頭にしたインデックスの一覧を返します。
+func (t *table) allIndexes() []*index {
+	if t.primaryKey == nil {
+		return t.indexes
+	}
+	return append([]*index{t.primaryKey}, t.indexes...)
+}
+
+// removeColumn はインデックスからカラムを取り除き、カラムが残っているかを返します。
+func (idx *index) removeColumn(name string) bool {
+	var parts []*sql_model.IndexColumn
+	for _, part := range idx.parts {
+		if part.Name == "" || !strings.EqualFold(part.Name, name) {
+			parts = append(parts, part)
+		}
+	}
+	idx.parts = parts
+	return len(parts) > 0
+}
+
+func (idx *index) clone() *index {
+	c := *idx
+	c.parts = nil
+	for _, part := range idx.parts {
+		copied := *part
+		c.parts = append(c.parts, &copied)
+	}
+	return &c
+}
+
+// addForeignKey は外部キーを追加します。名前が省略された場合はMySQLと同様に「テーブル名_ibfk_連番」とします。
+func (t *table) addForeignKey(fk *foreignKey) {
+	if fk.name == "" {
+		for n := 1; fk.name == "" || t.findForeignKey(fk.name) != nil; n++ {
+			fk.name = fmt.Sprintf("%s_ibfk_%d", t.name, n)
+		}
+	}
+	t.foreignKeys = append(t.foreignKeys, fk)
+}
+
+func (t *table) findIndex(name string) *index {
+	for _, idx := range t.indexes {
+		if strings.EqualFold(idx.name, name) {
+			return idx
+		}
+	}
+	return nil
+}
+
+func (t *table) findForeignKey(name string) *foreignKey {
+	for _, fk := range t.foreignKeys {
+		if strings.EqualFold(fk.name, name) {
+			return fk
+		}
+	}
+	return nil
+}
+
+// toModel はスキーマ定義をsql_model.DBへ変換します。
+// テーブル・ビューはDBから取得する場合と同じく、ファイル内の順序ではなく名前の順に並べます。
+func (s *schema) toModel() *sql_model.DB {
+	db := &sql_model.DB{Name: s.name}
+	for _, t := range s.tables {
+		db.Tables = append(db.Tables, t.toModel())
+	}
+	for _, v := range s.views {
+		db.Views = append(db.Views, v.toModel())
+	}
+	sort.Slice(db.Tables, func(i, j int) bool { return db.Tables[i].Name < db.Tables[j].Name })
+	sort.Slice(db.Views, func(i, j int) bool { return db.Views[i].Name < db.Views[j].Name })
+	db.Routines = s.routines
+	db.Events = s.events
+	return db
+}
+
+// toModel は制約単位の定義をカラムごとの主キー・ユニーク・インデックス・外部キー情報に展開します。
+func (t *table) toModel() *sql_model.Table {
+	var columns []*sql_model.Column
+	for _, c := range t.columns {
+		col := *c
+		t.applyCharset(&col)
+		columns = append(columns, &col)
+	}
+	result := &sql_model.Table{
+		Name:         t.name,
+		Columns:      columns,
+		Checks:       cloneChecks(t.checks),
+		Partitioning: clonePartitioning(t.partitioning),
+		Comment:      t.options["COMMENT"],
+		Engine:       t.options["ENGINE"],
+		Collation:    t.options["COLLATE"],
+		RowFormat:    t.options["ROW_FORMAT"],
+	}
+	if n, err := strconv.ParseInt(t.options["AUTO_INCREMENT"], 10, 64); err == nil {
+		result.AutoIncrement = &n
+	}
+
+	for _, idx := range t.allIndexes() {
+		result.Indexes = append(result.Indexes, idx.toModel(t.options["ENGINE"]))
+		for _, part := range idx.parts {
+			col := findColumn(columns, part.Name)
+			if col == nil {
+				continue
+			}
+			col.IsIndexed = true
+			if idx == t.primaryKey {
+				col.IsPrimaryKey = true
+				// 主キーのカラムは暗黙的に NOT NULL となる
+				col.IsNullable = false
+			} else if idx.isUnique {
+				setUnique(col, part.Length)
+			}
+		}
+	}
+	for _, fk := range t.foreignKeys {
+		result.ForeignKeys = append(result.ForeignKeys, fk.toModel())
+		for i, name := range fk.columns {
+			col := findColumn(columns, name)
+			if col == nil {
+				continue
+			}
+			// InnoDBは外部キーのカラムに対してインデックスを自動作成する
+			col.IsIndexed = true
+			if col.IsForeign || i >= len(fk.refColumns) {
+				continue
+			}
+			col.IsForeign = true
+			col.ForeignKeyTable = fk.refTable
+			col.ForeignKeyColumn = fk.refColumns[i]
+		}
+	}
+
+	orders := make(map[string]int64)
+	for _, trigger := range t.triggers {
+		copied := *trigger
+		orders[copied.Timing+" "+copied.Event]++
+		copied.Order = orders[copied.Timing+" "+copied.Event]
+		result.Triggers = append(result.Triggers, &copied)
+	}
+
+	return result
+}
+
+// setUnique はカラムにユニーク制約を設定します。length はプレフィックス長（カラム全体の場合は0）です。
+// カラム全体に対するユニーク制約がある場合はそれを優先し、プレフィックスのみの場合は最も短いプレフィックス長を残します。
+func setUnique(col *sql_model.Column, length int64) {
+	switch {
+	case !col.IsUnique:
+		col.UniquePrefixLength = length
+	case col.UniquePrefixLength == 0:
+		// カラム全体に対するユニーク制約がすでにある
+	case length == 0 || length < col.UniquePrefixLength:
+		col.UniquePrefixLength = length
+	}
+	col.IsUnique = true
+}
+
+// toModel はインデックス定義をsql_model.Indexへ変換します。種類の指定がない場合はエンジンの既定値とします。
+func (idx *index) toModel(engine string) *sql_model.Index {
+	typ := idx.typ
+	if typ == "" {
+		typ = "BTREE"
+		if strings.EqualFold(engine, "MEMORY") || strings.EqualFold(engine, "HEAP") {
+			typ = "HASH"
+		}
+	}
+	result := &sql_model.Index{
+		Name:      idx.name,
+		IsPrimary: idx.name == "PRIMARY",
+		IsUnique:  idx.isUnique,
+		Type:      typ,
+		IsVisible: !idx.invisible,
+		Comment:   idx.comment,
+	}
+	for _, part := range idx.parts {
+		copied := *part
+		result.Columns = append(result.Columns, &copied)
+	}
+	return result
+}
+
+// toModel は外部キー定義をsql_model.ForeignKeyへ変換します。参照アクションの省略時はMySQLと同様に NO ACTION とします。
+func (fk *foreignKey) toModel() *sql_model.ForeignKey {
+	result := &sql_model.ForeignKey{
+		Name:              fk.name,
+		Columns:           append([]string(nil), fk.columns...),
+		ReferencedTable:   fk.refTable,
+		ReferencedColumns: append([]string(nil), fk.refColumns...),
+		UpdateRule:        fk.onUpdate,
+		DeleteRule:        fk.onDelete,
+	}
+	if result.UpdateRule == "" {
+		result.UpdateRule = "NO ACTION"
+	}
+	if result.DeleteRule == "" {
+		result.DeleteRule = "NO ACTION"
+	}
+	return result
+}
+
+func findColumn(columns []*sql_model.Column, name string) *sql_model.Column {
+	for _, col := range columns {
+		if strings.EqualFold(col.Name, name) {
+			return col
+		}
+	}
+	return nil
+}
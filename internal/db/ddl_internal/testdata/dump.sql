@@ -0,0 +1,93 @@
+-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)
+--
+-- Host: localhost    Database: shop
+-- ------------------------------------------------------
+-- Server version	8.0.36
+
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET NAMES utf8mb4 */;
+/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
+
+--
+-- Table structure for table `users`
+--
+
+DROP TABLE IF EXISTS `users`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `users` (
+  `tenant_id` int unsigned NOT NULL,
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT 'ユーザーID',
+  `email` varchar(255) COLLATE utf8mb4_bin NOT NULL,
+  `bio` text,
+  `first_name` varchar(50) NOT NULL DEFAULT '',
+  `last_name` varchar(50) NOT NULL DEFAULT 'it''s',
+  `full_name` varchar(101) GENERATED ALWAYS AS (concat(`first_name`,_utf8mb4' ',`last_name`)) VIRTUAL,
+  `status` enum('active','it''s off') NOT NULL DEFAULT 'active',
+  `age` tinyint DEFAULT NULL,
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`tenant_id`,`id`),
+  UNIQUE KEY `uk_email` (`email`),
+  UNIQUE KEY `uk_bio` (`bio`(10)),
+  KEY `idx_id` (`id`),
+  KEY `idx_created` (`created_at` DESC),
+  KEY `idx_lower_email` ((lower(`email`))),
+  CONSTRAINT `chk_age` CHECK ((`age` >= 0)),
+  CONSTRAINT `users_chk_1` CHECK ((`status` <> _utf8mb4'x')) /*!80016 NOT ENFORCED */
+) ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='利用者';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `order``items`
+--
+
+DROP TABLE IF EXISTS `order``items`;
+CREATE TABLE `order``items` (
+  `tenant_id` int unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `line``no` int NOT NULL,
+  `ordered_on` date NOT NULL,
+  PRIMARY KEY (`tenant_id`,`user_id`,`line``no`,`ordered_on`),
+  KEY `fk_user` (`tenant_id`,`user_id`),
+  CONSTRAINT `fk_user` FOREIGN KEY (`tenant_id`, `user_id`) REFERENCES `users` (`tenant_id`, `id`) ON DELETE CASCADE ON UPDATE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+/*!50100 PARTITION BY RANGE (year(`ordered_on`))
+(PARTITION p2023 VALUES LESS THAN (2024) ENGINE = InnoDB,
+ PARTITION p2024 VALUES LESS THAN (2025) ENGINE = InnoDB,
+ PARTITION pmax VALUES LESS THAN MAXVALUE ENGINE = InnoDB) */;
+
+--
+-- Table structure for table `audit`
+--
+
+DROP TABLE IF EXISTS `audit`;
+CREATE TABLE `audit` (
+  `id` int NOT NULL,
+  `note` varchar(100) DEFAULT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+--
+-- Dumping routines for database 'shop'
+--
+/*!50003 DROP FUNCTION IF EXISTS `full_name_of` */;
+DELIMITER ;;
+CREATE DEFINER=`root`@`localhost` FUNCTION `full_name_of`(p_id BIGINT) RETURNS varchar(101) CHARSET utf8mb4
+    READS SQL DATA
+BEGIN
+  DECLARE result VARCHAR(101);
+  SELECT full_name INTO result FROM users WHERE id = p_id;
+  RETURN result;
+END ;;
+DELIMITER ;
+
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY' */ ;
+DELIMITER ;;
+/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`localhost`*/ /*!50003 TRIGGER `users_bi` BEFORE INSERT ON `users` FOR EACH ROW BEGIN
+  SET NEW.email = lower(NEW.email);
+END */;;
+DELIMITER ;
+
+/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
+-- Dump completed on 2024-01-01 00:00:00
@@ -0,0 +1,2 @@
+ALTER TABLE accounts ADD COLUMN email varchar(255);
+CREATE TABLE sessions (id int NOT NULL);
@@ -0,0 +1 @@
+ALTER TABLE accounts CHANGE name full_name varchar(100) NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE users
+  ADD COLUMN email varchar(255) NOT NULL AFTER id,
+  DROP COLUMN legacy,
+  MODIFY name varchar(100) NOT NULL;
+ALTER TABLE users RENAME COLUMN name TO display_name;
+ALTER TABLE posts RENAME TO articles;
+DROP TABLE tmp;
@@ -0,0 +1,6 @@
+CREATE TABLE users (
+  id int NOT NULL AUTO_INCREMENT,
+  name varchar(50),
+  legacy int,
+  PRIMARY KEY (id)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE tmp (id int);
+
+CREATE TABLE posts (
+  id int NOT NULL,
+  user_id int,
+  PRIMARY KEY (id),
+  KEY idx_user (user_id)
+);
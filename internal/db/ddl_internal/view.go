@@ -0,0 +1,225 @@
+package ddl_internal
+
+import (
+	"export-db-info/internal/model/sql_model"
+	"strings"
+)
+
+// view はDDLから読み取ったビュー定義です。
+type view struct {
+	name             string
+	columns          []string // カラム名（型はDDLから判別できないため保持しない）
+	definition       string
+	securityType     string
+	checkOption      string
+	referencedTables []string
+}
+
+func (s *schema) findView(name string) *view {
+	for _, v := range s.views {
+		if strings.EqualFold(v.name, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// parseCreateObject は CREATE に続く OR REPLACE / ALGORITHM / DEFINER / SQL SECURITY を読み取り、
+// ビュー・トリガー・ルーチン・イベントの定義を解析します。それ以外の文は何もしません。
+func (s *schema) parseCreateObject(p *parser) error {
+	securityType, definer := "DEFINER", ""
+	for !p.atEnd() {
+		switch {
+		case p.accept("OR", "REPLACE"):
+		case p.accept("ALGORITHM"):
+			p.acceptSymbol("=")
+			p.next()
+		case p.accept("DEFINER"):
+			// DEFINER=`user`@`host` は information_schema と同じ user@host の形式で保持する
+			p.acceptSymbol("=")
+			definer = ""
+			for !p.atEnd() && !p.peek().is("SQL", "VIEW", "TRIGGER", "PROCEDURE", "FUNCTION", "EVENT") {
+				definer += p.next().text
+			}
+		case p.accept("SQL", "SECURITY"):
+			securityType = strings.ToUpper(p.next().text)
+		case p.accept("VIEW"):
+			return s.parseCreateView(p, securityType)
+		case p.accept("TRIGGER"):
+			return s.parseCreateTrigger(p, definer)
+		case p.accept("PROCEDURE"):
+			return s.parseCreateRoutine(p, "PROCEDURE", definer)
+		case p.accept("FUNCTION"):
+			return s.parseCreateRoutine(p, "FUNCTION", definer)
+		case p.accept("EVENT"):
+			return s.parseCreateEvent(p, definer)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// parseCreateView は VIEW 以降のビュー定義を解析します。同名のビューが既にある場合は置き換えます。
+func (s *schema) parseCreateView(p *parser, securityType string) error {
+	p.accept("IF", "NOT", "EXISTS")
+	name, err := p.parseName()
+	if err != nil {
+		return err
+	}
+
+	v := &view{name: name, securityType: securityType, checkOption: "NONE"}
+	if p.peek().isSymbol("(") {
+		if v.columns, err = p.parseKeyColumns(); err != nil {
+			return err
+		}
+	}
+	if err := p.expect("AS"); err != nil {
+		return err
+	}
+
+	query := p.tokens[p.pos:]
+	// WITH [CASCADED | LOCAL] CHECK OPTION は定義のSELECT文に含めない
+	if n := len(query); n >= 3 && query[n-2].is("CHECK") && query[n-1].is("OPTION") {
+		v.checkOption = "CASCADED"
+		query = query[:n-2]
+		if n := len(query); n > 0 && query[n-1].is("CASCADED", "LOCAL") {
+			v.checkOption = strings.ToUpper(query[n-1].text)
+			query = query[:n-1]
+		}
+		if n := len(query); n > 0 && query[n-1].is("WITH") {
+			query = query[:n-1]
+		}
+	}
+
+	v.definition = joinTokens(query)
+	if v.columns == nil {
+		v.columns = selectColumnNames(query)
+	}
+	v.referencedTables = referencedTableNames(query)
+
+	// mysqldump はビューの代わりに同名の仮テーブルを先に作成するため、残っていれば取り除く
+	s.dropTable(name)
+	s.dropView(name)
+	s.views = append(s.views, v)
+	return nil
+}
+
+// parseDropView は DROP VIEW [IF EXISTS] v1 [, v2] 文を解析します。
+func (s *schema) parseDropView(p *parser) error {
+	p.accept("IF", "EXISTS")
+	for {
+		name, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		s.dropView(name)
+		if !p.acceptSymbol(",") {
+			return nil
+		}
+	}
+}
+
+func (s *schema) dropView(name string) {
+	for i, v := range s.views {
+		if strings.EqualFold(v.name, name) {
+			s.views = append(s.views[:i], s.views[i+1:]...)
+			return
+		}
+	}
+}
+
+// selectColumnNames はSELECT文の選択リストからビューのカラム名を求めます。
+// 別名（AS）がある場合は別名、`t`.`col` のようなカラム参照の場合はカラム名、それ以外は式をそのまま名前とします。
+func selectColumnNames(query []token) []string {
+	start := -1
+	for i, t := range query {
+		if t.is("SELECT") {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+
+	// 選択リストは同じ階層の FROM までとする
+	end, depth := len(query), 0
+	for i := start; i < len(query); i++ {
+		t := query[i]
+		if t.isSymbol("(") {
+			depth++
+		} else if t.isSymbol(")") {
+			depth--
+		} else if depth == 0 && t.is("FROM") {
+			end = i
+			break
+		}
+	}
+
+	var names []string
+	for _, item := range splitTopLevel(query[start:end]) {
+		for len(item) > 0 && item[0].is("DISTINCT", "ALL", "DISTINCTROW", "SQL_NO_CACHE", "SQL_CALC_FOUND_ROWS") {
+			item = item[1:]
+		}
+		n := len(item)
+		switch {
+		case n == 0:
+			continue
+		case n >= 2 && item[n-2].is("AS") && item[n-1].isName():
+			names = append(names, item[n-1].text)
+		case item[n-1].isName() && (n == 1 || item[n-2].isSymbol(".")):
+			names = append(names, item[n-1].text)
+		case n >= 2 && item[n-1].isName() && !item[n-2].isSymbol("."):
+			// AS を省略した別名
+			names = append(names, item[n-1].text)
+		default:
+			names = append(names, joinTokens(item))
+		}
+	}
+	return names
+}
+
+// referencedTableNames はSELECT文の FROM / JOIN 句に現れるテーブル名を重複を除いて返します。
+// サブクエリや括弧で囲まれた結合の内側も対象とし、スキーマ修飾は取り除きます。
+func referencedTableNames(query []token) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for i := 0; i < len(query); i++ {
+		if !query[i].is("FROM", "JOIN") {
+			continue
+		}
+		j := i + 1
+		for j < len(query) && query[j].isSymbol("(") {
+			j++
+		}
+		if j >= len(query) || !query[j].isName() || query[j].is("SELECT", "LATERAL", "JSON_TABLE") {
+			continue
+		}
+		name := query[j].text
+		for j+2 < len(query) && query[j+1].isSymbol(".") && query[j+2].isName() {
+			name = query[j+2].text
+			j += 2
+		}
+		if !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// toModel はビュー定義をsql_model.Viewへ変換します。
+func (v *view) toModel() *sql_model.View {
+	result := &sql_model.View{
+		Name:             v.name,
+		Definition:       v.definition,
+		SecurityType:     v.securityType,
+		CheckOption:      v.checkOption,
+		ReferencedTables: append([]string(nil), v.referencedTables...),
+	}
+	for _, name := range v.columns {
+		result.Columns = append(result.Columns, &sql_model.Column{Name: name, IsNullable: true})
+	}
+	return result
+}
@@ -0,0 +1,10 @@
+// Package drivers は利用可能なすべてのSchemaInspectorドライバを登録します。
+// 新しいDBエンジンを追加する場合は、ここにブランクインポートを追加してください。
+package drivers
+
+import (
+	_ "export-db-info/internal/db/ddl_internal"
+	_ "export-db-info/internal/db/mysql_internal"
+	_ "export-db-info/internal/db/postgres_internal"
+	_ "export-db-info/internal/db/sqlite_internal"
+)
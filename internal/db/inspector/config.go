@@ -0,0 +1,148 @@
+package inspector
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config はSchemaInspectorの接続設定を保持します。
+type Config struct {
+	Driver       string        // DBエンジン（mysql / postgres / sqlite）
+	Host         string        // ホスト名
+	Port         string        // ポート番号
+	Database     string        // データベース名
+	Username     string        // ユーザー名
+	Password     string        // パスワード
+	Schemas      []string      // 対象スキーマ（PostgreSQLのみ）
+	MultiSchemas []string      // スキーマごとに個別に取得する対象スキーマの名前またはグロブ（MySQL / PostgreSQLのみ）
+	SSLMode      string        // SSLモード（PostgreSQLのみ）
+	Socket       string        // UNIXソケットのパス（PostgreSQLはソケットのディレクトリ）
+	DSN          string        // 接続文字列（指定した場合はホスト名などの接続設定の代わりに使う）
+	Params       url.Values    // 接続文字列に追加するドライバのパラメータ
+	Path         string        // データベースファイルのパス（SQLiteのみ）
+	Workers      int           // テーブル情報を並列に取得するワーカー数（0以下の場合は DefaultWorkers）
+	Timeout      time.Duration // 取得全体のタイムアウト（0の場合は無制限）
+	QueryTimeout time.Duration // メタデータの問い合わせごとのタイムアウト（0の場合は無制限）
+	Snapshot     bool          // すべての問い合わせを同じスナップショットの読み取り専用トランザクション内で実行するかどうか
+	AllowWrite   bool          // 書き込み権限を持つユーザーでの実行を許可するかどうか
+	Grants       bool          // ユーザーの権限の情報を取得するかどうか（MySQLのみ）
+	IndexUsage   bool          // 使われていない・冗長なインデックスを調べるかどうか（MySQLのみ）
+
+	Profile           bool          // テーブルのデータをサンプリングしてカラムの値の傾向を調べるかどうか
+	ProfileSampleRows int           // テーブルごとにサンプリングする最大行数（0以下の場合は DefaultProfileSampleRows）
+	ProfileTopN       int           // 出現回数の多い値を表示する数（0以下の場合は DefaultProfileTopN）
+	ProfileTimeout    time.Duration // テーブルごとのサンプリングの時間の上限（0の場合は DefaultProfileTimeout）
+
+	IncludeTables  []string // 対象とするテーブル・ビューのパターン（未指定の場合はすべて）
+	ExcludeTables  []string // 除外するテーブル・ビューのパターン
+	IncludeColumns []string // 対象とするカラムのパターン（未指定の場合はすべて）
+	ExcludeColumns []string // 除外するカラムのパターン
+
+	TLS           bool   // TLSで接続するかどうか（証明書のファイルを指定した場合は常にTLSを使う）
+	TLSCA         string // サーバー証明書の検証に使うCA証明書のファイル
+	TLSCert       string // クライアント証明書のファイル
+	TLSKey        string // クライアント証明書の秘密鍵のファイル
+	TLSSkipVerify bool   // サーバー証明書を検証しない（開発環境向け）
+}
+
+// ConfigFromEnv は環境変数から接続設定を読み込みます。DB_DRIVERが未指定の場合は mysql とします。
+func ConfigFromEnv() *Config {
+	cfg := &Config{
+		Driver:   os.Getenv("DB_DRIVER"),
+		Host:     os.Getenv("DB_HOST"),
+		Port:     os.Getenv("DB_PORT"),
+		Database: os.Getenv("DB_DATABASE"),
+		Username: os.Getenv("DB_USERNAME"),
+		Password: os.Getenv("DB_PASSWORD"),
+		Schemas:  splitList(os.Getenv("DB_SCHEMAS")),
+		SSLMode:  os.Getenv("DB_SSLMODE"),
+		Socket:   os.Getenv("DB_SOCKET"),
+		DSN:      os.Getenv("DB_DSN"),
+		Params:   parseQuery(os.Getenv("DB_PARAMS")),
+		Path:     os.Getenv("DB_PATH"),
+
+		MultiSchemas: splitList(os.Getenv("DB_MULTI_SCHEMAS")),
+		Workers:      atoi(os.Getenv("DB_WORKERS")),
+		Timeout:      parseDuration(os.Getenv("DB_TIMEOUT")),
+		QueryTimeout: parseDuration(os.Getenv("DB_QUERY_TIMEOUT")),
+		Snapshot:     parseBool(os.Getenv("DB_SNAPSHOT"), true),
+		AllowWrite:   parseBool(os.Getenv("DB_ALLOW_WRITE_USER"), false),
+		Grants:       parseBool(os.Getenv("DB_GRANTS"), false),
+		IndexUsage:   parseBool(os.Getenv("DB_INDEX_USAGE"), false),
+
+		Profile:           parseBool(os.Getenv("DB_PROFILE"), false),
+		ProfileSampleRows: atoi(os.Getenv("DB_PROFILE_SAMPLE_ROWS")),
+		ProfileTopN:       atoi(os.Getenv("DB_PROFILE_TOP_N")),
+		ProfileTimeout:    parseDuration(os.Getenv("DB_PROFILE_TIMEOUT")),
+
+		IncludeTables:  splitList(os.Getenv("DB_INCLUDE_TABLES")),
+		ExcludeTables:  splitList(os.Getenv("DB_EXCLUDE_TABLES")),
+		IncludeColumns: splitList(os.Getenv("DB_INCLUDE_COLUMNS")),
+		ExcludeColumns: splitList(os.Getenv("DB_EXCLUDE_COLUMNS")),
+
+		TLS:           parseBool(os.Getenv("DB_TLS"), false),
+		TLSCA:         os.Getenv("DB_TLS_CA"),
+		TLSCert:       os.Getenv("DB_TLS_CERT"),
+		TLSKey:        os.Getenv("DB_TLS_KEY"),
+		TLSSkipVerify: parseBool(os.Getenv("DB_TLS_SKIP_VERIFY"), false),
+	}
+	if cfg.Driver == "" {
+		cfg.Driver = "mysql"
+	}
+	return cfg
+}
+
+// splitList はカンマ区切りの値を分解し、空要素を取り除きます。
+func splitList(value string) []string {
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// atoi は整数の値を変換します。未指定または整数でない場合は 0 を返します。
+func atoi(value string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// parseDuration は「30s」「5m」形式の時間を変換します。未指定または解釈できない場合は 0 を返します。
+func parseDuration(value string) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// parseBool は真偽値を変換します。未指定または解釈できない場合は defaultValue を返します。
+func parseBool(value string, defaultValue bool) bool {
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return defaultValue
+	}
+	return b
+}
+
+// parseQuery は「key=value&key2=value2」形式のパラメータを変換します。解釈できない場合は nil を返します。
+func parseQuery(value string) url.Values {
+	params, err := url.ParseQuery(strings.TrimSpace(value))
+	if err != nil {
+		return nil
+	}
+	return params
+}
+
+// UseTLS はTLSで接続するかどうかを返します。
+func (cfg *Config) UseTLS() bool {
+	return cfg.TLS || cfg.TLSCA != "" || cfg.TLSCert != "" || cfg.TLSSkipVerify
+}
@@ -0,0 +1,245 @@
+package inspector
+
+import (
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// namePattern はテーブル名・カラム名に対するパターンです。
+// 「/」で囲んだパターンは正規表現、それ以外は path.Match 形式のグロブとして扱います。
+type namePattern struct {
+	glob   string
+	regexp *regexp.Regexp
+}
+
+func (p namePattern) match(name string) bool {
+	if p.regexp != nil {
+		return p.regexp.MatchString(name)
+	}
+	ok, _ := path.Match(p.glob, name)
+	return ok
+}
+
+// compilePatterns はパターンの一覧を解析します。
+func compilePatterns(patterns []string) ([]namePattern, error) {
+	var compiled []namePattern
+	for _, pattern := range patterns {
+		if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			compiled = append(compiled, namePattern{regexp: re})
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, namePattern{glob: pattern})
+	}
+	return compiled, nil
+}
+
+// nameFilter は対象に含めるパターンと除外するパターンの組です。
+type nameFilter struct {
+	include, exclude []namePattern
+}
+
+// accept は names のいずれかが対象に含めるパターン（未指定の場合はすべて）に一致し、
+// どれも除外するパターンに一致しない場合に true を返します。
+func (f nameFilter) accept(names ...string) bool {
+	matchAny := func(patterns []namePattern) bool {
+		for _, p := range patterns {
+			for _, name := range names {
+				if p.match(name) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return (len(f.include) == 0 || matchAny(f.include)) && !matchAny(f.exclude)
+}
+
+func newNameFilter(include, exclude []string) (nameFilter, error) {
+	var f nameFilter
+	var err error
+	if f.include, err = compilePatterns(include); err != nil {
+		return f, err
+	}
+	if f.exclude, err = compilePatterns(exclude); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// Filter は取得したスキーマ情報からテーブル・ビューとカラムを名前で絞り込みます。
+type Filter struct {
+	tables  nameFilter
+	columns nameFilter
+}
+
+// NewFilter は接続設定の対象・除外パターンからFilterを生成します。
+func NewFilter(cfg *Config) (*Filter, error) {
+	tables, err := newNameFilter(cfg.IncludeTables, cfg.ExcludeTables)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := newNameFilter(cfg.IncludeColumns, cfg.ExcludeColumns)
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{tables: tables, columns: columns}, nil
+}
+
+// AcceptTable はテーブル・ビューを取得の対象とするかどうかを返します。
+// 各ドライバはテーブル単位の問い合わせの前にこれで絞り込み、除外したテーブルの情報を取得しません。
+// f が nil の場合はすべてのテーブルを対象とします。
+func (f *Filter) AcceptTable(name string) bool {
+	return f == nil || f.tables.accept(name)
+}
+
+// Apply はパターンに一致しないテーブル・ビューとカラムを dbInfo から取り除きます。
+// カラムのパターンはカラム名と「テーブル名.カラム名」の両方に対して照合します。
+// 除外したカラムを参照するインデックス・外部キー・CHECK制約と生成列の式も出力しません
+// （トリガー・パーティション式・ビューの定義はそのまま出力します）。
+func (f *Filter) Apply(dbInfo *sql_model.DB) {
+	var tables []*sql_model.Table
+	for _, table := range dbInfo.Tables {
+		if f.tables.accept(table.Name) {
+			columns := f.filterColumns(table.Name, table.Columns)
+			if len(columns) < len(table.Columns) {
+				excluded := make(map[string]bool)
+				for _, col := range table.Columns {
+					excluded[strings.ToLower(col.Name)] = true
+				}
+				for _, col := range columns {
+					delete(excluded, strings.ToLower(col.Name))
+				}
+				table.Columns = columns
+				removeExcludedColumns(table, excluded)
+			}
+			tables = append(tables, table)
+		}
+	}
+	dbInfo.Tables = tables
+
+	var views []*sql_model.View
+	for _, view := range dbInfo.Views {
+		if f.tables.accept(view.Name) {
+			view.Columns = f.filterColumns(view.Name, view.Columns)
+			views = append(views, view)
+		}
+	}
+	dbInfo.Views = views
+
+	// 除外したテーブル・カラムに対する権限とインデックスの調査結果も除く
+	var grants []*sql_model.Grant
+	for _, grant := range dbInfo.Grants {
+		if grant.Table != "" && !f.tables.accept(grant.Table) {
+			continue
+		}
+		if grant.Column != "" && !f.columns.accept(grant.Column, grant.Table+"."+grant.Column) {
+			continue
+		}
+		grants = append(grants, grant)
+	}
+	dbInfo.Grants = grants
+
+	var reports []*sql_model.IndexReport
+	for _, report := range dbInfo.IndexReports {
+		if f.tables.accept(report.Table) {
+			reports = append(reports, report)
+		}
+	}
+	dbInfo.IndexReports = reports
+}
+
+func (f *Filter) filterColumns(tableName string, columns []*sql_model.Column) []*sql_model.Column {
+	var filtered []*sql_model.Column
+	for _, col := range columns {
+		if f.columns.accept(col.Name, tableName+"."+col.Name) {
+			filtered = append(filtered, col)
+		}
+	}
+	return filtered
+}
+
+// removeExcludedColumns は除外したカラム（excluded は小文字のカラム名）を参照するインデックス・外部キー・CHECK制約をテーブルから取り除きます。
+// 複合インデックスからカラムだけを除くと残りのカラムで一意であるかのように見えるため、インデックス・外部キーは丸ごと除きます。
+func removeExcludedColumns(table *sql_model.Table, excluded map[string]bool) {
+	var indexes []*sql_model.Index
+	for _, idx := range table.Indexes {
+		if !indexReferencesAny(idx, excluded) {
+			indexes = append(indexes, idx)
+		}
+	}
+	table.Indexes = indexes
+
+	var foreignKeys []*sql_model.ForeignKey
+	for _, fk := range table.ForeignKeys {
+		if !containsAny(fk.Columns, excluded) {
+			foreignKeys = append(foreignKeys, fk)
+		}
+	}
+	table.ForeignKeys = foreignKeys
+
+	var checks []*sql_model.Check
+	for _, check := range table.Checks {
+		if !referencesAny(check.Expression, excluded) {
+			checks = append(checks, check)
+		}
+	}
+	table.Checks = checks
+
+	for _, col := range table.Columns {
+		if referencesAny(col.Expression, excluded) {
+			col.Expression = ""
+		}
+	}
+}
+
+// indexReferencesAny はインデックスの構成カラム・式・部分インデックスの条件が names のいずれかを参照するかどうかを返します。
+func indexReferencesAny(idx *sql_model.Index, names map[string]bool) bool {
+	if referencesAny(idx.Condition, names) {
+		return true
+	}
+	for _, part := range idx.Columns {
+		if names[strings.ToLower(part.Name)] || referencesAny(part.Expression, names) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAny は columns が names（小文字のカラム名）のいずれかを含むかどうかを返します。
+func containsAny(columns []string, names map[string]bool) bool {
+	for _, name := range columns {
+		if names[strings.ToLower(name)] {
+			return true
+		}
+	}
+	return false
+}
+
+// identifierPattern は式の中の文字列リテラルと識別子（クォートした識別子を含む）です。
+var identifierPattern = regexp.MustCompile("'(?:[^']|'')*'|`((?:[^`]|``)+)`|\"((?:[^\"]|\"\")+)\"|\\[([^\\]]+)\\]|([\\pL_][\\pL\\pN_$]*)")
+
+// referencesAny は式が names（小文字のカラム名）のいずれかを識別子として含むかどうかを返します。文字列リテラルの中は照合しません。
+func referencesAny(expression string, names map[string]bool) bool {
+	if expression == "" {
+		return false
+	}
+	for _, m := range identifierPattern.FindAllStringSubmatch(expression, -1) {
+		// クォートした識別子の中の二重にした引用符は1文字に戻して照合する
+		for _, name := range []string{strings.ReplaceAll(m[1], "``", "`"), strings.ReplaceAll(m[2], `""`, `"`), m[3], m[4]} {
+			if name != "" && names[strings.ToLower(name)] {
+				return true
+			}
+		}
+	}
+	return false
+}
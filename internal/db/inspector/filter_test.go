@@ -0,0 +1,219 @@
+package inspector
+
+import (
+	"export-db-info/internal/model/sql_model"
+	"reflect"
+	"testing"
+)
+
+// グロブと「/」で囲んだ正規表現を区別し、不正なパターンをエラーにすること
+func TestCompilePatterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+		wantErr bool
+	}{
+		{pattern: "tmp_*", match: []string{"tmp_", "tmp_users"}, noMatch: []string{"users_tmp", "xtmp_a"}},
+		{pattern: "user?", match: []string{"users"}, noMatch: []string{"user", "userss"}},
+		{pattern: "/^tmp_\\d+$/", match: []string{"tmp_1", "tmp_20240101"}, noMatch: []string{"tmp_a", "xtmp_1"}},
+		{pattern: "/log/", match: []string{"log", "access_log_2024"}, noMatch: []string{"users"}},
+		{pattern: "/", match: []string{"/"}, noMatch: []string{"a"}},
+		{pattern: "/(/", wantErr: true},
+		{pattern: "[a-", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			patterns, err := compilePatterns([]string{tt.pattern})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got no error for %q", tt.pattern)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range tt.match {
+				if !patterns[0].match(name) {
+					t.Errorf("%q does not match %q", tt.pattern, name)
+				}
+			}
+			for _, name := range tt.noMatch {
+				if patterns[0].match(name) {
+					t.Errorf("%q matches %q", tt.pattern, name)
+				}
+			}
+		})
+	}
+}
+
+// 除外パターンが対象パターンより優先され、「テーブル名.カラム名」でも照合すること
+func TestNameFilterAccept(t *testing.T) {
+	tests := []struct {
+		name             string
+		include, exclude []string
+		names            []string
+		want             bool
+	}{
+		{name: "no patterns", names: []string{"users"}, want: true},
+		{name: "included", include: []string{"user*"}, names: []string{"users"}, want: true},
+		{name: "not included", include: []string{"user*"}, names: []string{"orders"}, want: false},
+		{name: "excluded", exclude: []string{"tmp_*"}, names: []string{"tmp_users"}, want: false},
+		{name: "exclude wins over include", include: []string{"*"}, exclude: []string{"tmp_*"}, names: []string{"tmp_users"}, want: false},
+		{name: "column name", exclude: []string{"password"}, names: []string{"password", "users.password"}, want: false},
+		{name: "qualified column", exclude: []string{"users.password"}, names: []string{"password", "users.password"}, want: false},
+		{name: "qualified column of other table", exclude: []string{"users.password"}, names: []string{"password", "admins.password"}, want: true},
+		{name: "qualified glob", include: []string{"users.*"}, names: []string{"email", "users.email"}, want: true},
+		{name: "qualified regexp", exclude: []string{"/^[a-z]+\\.secret_/"}, names: []string{"secret_key", "users.secret_key"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newNameFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.accept(tt.names...); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// 識別子として参照している場合のみ一致し、文字列リテラルの中は無視すること
+func TestReferencesAny(t *testing.T) {
+	names := map[string]bool{"password": true, "a`b": true, "full name": true}
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"", false},
+		{"password IS NOT NULL", true},
+		{"PASSWORD <> ''", true},
+		{"char_length(`password`) >= 8", true},
+		{`"password" IS NOT NULL`, true},
+		{"[password] IS NOT NULL", true},
+		{"users.password IS NOT NULL", true},
+		{"status <> 'password'", false},
+		{"status <> 'it''s password'", false},
+		{"password_hash IS NOT NULL", false},
+		{"`password_hash` IS NOT NULL", false},
+		{"`a``b` > 0", true},
+		{`"full name" <> ''`, true},
+		{"[full name] <> ''", true},
+		{"full_name <> ''", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			if got := referencesAny(tt.expression, names); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// 除外したカラムを構成カラム・式・条件に含むインデックスを判定すること
+func TestIndexReferencesAny(t *testing.T) {
+	names := map[string]bool{"email": true}
+	tests := []struct {
+		name  string
+		index *sql_model.Index
+		want  bool
+	}{
+		{
+			name:  "column",
+			index: &sql_model.Index{Columns: []*sql_model.IndexColumn{{Name: "tenant_id"}, {Name: "Email"}}},
+			want:  true,
+		},
+		{
+			name:  "other column",
+			index: &sql_model.Index{Columns: []*sql_model.IndexColumn{{Name: "email_verified"}}},
+			want:  false,
+		},
+		{
+			name:  "expression",
+			index: &sql_model.Index{Columns: []*sql_model.IndexColumn{{Expression: "lower(`email`)"}}},
+			want:  true,
+		},
+		{
+			name:  "literal in expression",
+			index: &sql_model.Index{Columns: []*sql_model.IndexColumn{{Expression: "coalesce(name, 'email')"}}},
+			want:  false,
+		},
+		{
+			name:  "partial index condition",
+			index: &sql_model.Index{Columns: []*sql_model.IndexColumn{{Name: "id"}}, Condition: `("email" IS NOT NULL)`},
+			want:  true,
+		},
+		{
+			name:  "literal in condition",
+			index: &sql_model.Index{Columns: []*sql_model.IndexColumn{{Name: "id"}}, Condition: "(kind = 'email')"},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := indexReferencesAny(tt.index, names); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// 除外したカラムを参照するインデックス・外部キー・CHECK制約・生成列の式を取り除くこと
+func TestRemoveExcludedColumns(t *testing.T) {
+	table := &sql_model.Table{
+		Name: "users",
+		Columns: []*sql_model.Column{
+			{Name: "id"},
+			{Name: "tenant_id"},
+			{Name: "name"},
+			{Name: "email_domain", Generated: "STORED", Expression: "substring_index(`Email`, '@', -1)"},
+			{Name: "label", Generated: "VIRTUAL", Expression: "concat(name, ' <email>')"},
+		},
+		Indexes: []*sql_model.Index{
+			{Name: "PRIMARY", Columns: []*sql_model.IndexColumn{{Name: "id"}}},
+			{Name: "uk_tenant_email", Columns: []*sql_model.IndexColumn{{Name: "tenant_id"}, {Name: "email"}}},
+			{Name: "idx_lower_email", Columns: []*sql_model.IndexColumn{{Expression: "lower(email)"}}},
+			{Name: "idx_name", Columns: []*sql_model.IndexColumn{{Name: "name"}}, Condition: "[email] IS NOT NULL"},
+			{Name: "idx_tenant", Columns: []*sql_model.IndexColumn{{Name: "tenant_id"}}, Condition: "name <> 'email'"},
+		},
+		ForeignKeys: []*sql_model.ForeignKey{
+			{Name: "fk_tenant", Columns: []string{"tenant_id"}},
+			{Name: "fk_contact", Columns: []string{"tenant_id", "EMAIL"}},
+		},
+		Checks: []*sql_model.Check{
+			{Name: "chk_email", Expression: `("email" LIKE '%@%')`},
+			{Name: "chk_name", Expression: "(name <> 'email')"},
+		},
+	}
+	removeExcludedColumns(table, map[string]bool{"email": true})
+
+	var indexes, foreignKeys, checks []string
+	for _, idx := range table.Indexes {
+		indexes = append(indexes, idx.Name)
+	}
+	for _, fk := range table.ForeignKeys {
+		foreignKeys = append(foreignKeys, fk.Name)
+	}
+	for _, check := range table.Checks {
+		checks = append(checks, check.Name)
+	}
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"indexes", indexes, []string{"PRIMARY", "idx_tenant"}},
+		{"foreign keys", foreignKeys, []string{"fk_tenant"}},
+		{"checks", checks, []string{"chk_name"}},
+		{"expressions", []string{table.Columns[3].Expression, table.Columns[4].Expression}, []string{"", "concat(name, ' <email>')"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
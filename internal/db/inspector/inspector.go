@@ -0,0 +1,62 @@
+package inspector
+
+import (
+	"context"
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// SchemaInspector はDBエンジンからスキーマ情報（テーブル・カラム定義）を取得します。
+type SchemaInspector interface {
+	Inspect(ctx context.Context) (*sql_model.DB, error)
+}
+
+// Factory は接続設定からSchemaInspectorを生成する関数です。
+type Factory func(cfg *Config) (SchemaInspector, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register はドライバ名に対応するSchemaInspectorの生成関数を登録します。
+// 各ドライバパッケージの init から呼び出されることを想定しており、同じ名前で二重に登録した場合は panic します。
+func Register(driver string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("inspector: Register factory is nil")
+	}
+	if _, dup := factories[driver]; dup {
+		panic("inspector: Register called twice for driver " + driver)
+	}
+	factories[driver] = factory
+}
+
+// Drivers は登録済みのドライバ名をソートして返します。
+func Drivers() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	var drivers []string
+	for driver := range factories {
+		drivers = append(drivers, driver)
+	}
+	sort.Strings(drivers)
+	return drivers
+}
+
+// New は設定されたドライバのSchemaInspectorを生成します。
+func New(cfg *Config) (SchemaInspector, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[cfg.Driver]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unsupported DB_DRIVER: %s (available: %v)", cfg.Driver, Drivers())
+	}
+	return factory(cfg)
+}
@@ -0,0 +1,93 @@
+package inspector
+
+import (
+	"context"
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"path"
+)
+
+// MultiSchemaInspector は1つの接続から複数のスキーマをスキーマごとに取得できるSchemaInspectorです。
+// MySQLではデータベース、PostgreSQLではスキーマが1つの sql_model.DB に対応します。
+type MultiSchemaInspector interface {
+	SchemaInspector
+	// InspectSchemas はシステムスキーマを除くスキーマのうち、patterns のいずれかに一致するものをスキーマ名の順に取得します。
+	InspectSchemas(ctx context.Context, patterns []string) ([]*sql_model.DB, error)
+}
+
+// InspectAll は接続設定に従ってスキーマ情報を取得し、テーブル・カラムのパターンで絞り込みます。
+// Profile が指定されている場合は、絞り込んだテーブルのデータをサンプリングしてカラムの値の傾向も調べます。
+// MultiSchemas が指定されている場合は一致したスキーマごとに、それ以外は Inspect の結果1件を返します。
+// 取得全体には Timeout を、各問い合わせには QueryTimeout をタイムアウトとして設定します。
+func InspectAll(ctx context.Context, cfg *Config) ([]*sql_model.DB, error) {
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+	ctx = WithQueryTimeout(ctx, cfg.QueryTimeout)
+
+	filter, err := NewFilter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	schemaInspector, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbInfos []*sql_model.DB
+	if len(cfg.MultiSchemas) == 0 {
+		dbInfo, err := schemaInspector.Inspect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		dbInfos = []*sql_model.DB{dbInfo}
+	} else {
+		multi, ok := schemaInspector.(MultiSchemaInspector)
+		if !ok {
+			return nil, fmt.Errorf("DB_DRIVER %s does not support DB_MULTI_SCHEMAS", cfg.Driver)
+		}
+		if dbInfos, err = multi.InspectSchemas(ctx, cfg.MultiSchemas); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, dbInfo := range dbInfos {
+		filter.Apply(dbInfo)
+	}
+
+	// 除外したカラムのデータは読まないよう、プロファイリングは絞り込んだ後に行う
+	if cfg.Profile {
+		profiler, ok := schemaInspector.(Profiler)
+		if !ok {
+			return nil, fmt.Errorf("DB_DRIVER %s does not support DB_PROFILE", cfg.Driver)
+		}
+		if err := profiler.Profile(ctx, dbInfos); err != nil {
+			return nil, err
+		}
+	}
+	return dbInfos, nil
+}
+
+// MatchSchemas は schemas のうち、patterns のいずれかに一致するものを元の順序で返します。
+// パターンには path.Match の形式のグロブを指定でき、「*」はすべてのスキーマに一致します。
+func MatchSchemas(schemas, patterns []string) ([]string, error) {
+	var matched []string
+	for _, schema := range schemas {
+		for _, pattern := range patterns {
+			ok, err := path.Match(pattern, schema)
+			if err != nil {
+				return nil, fmt.Errorf("invalid schema pattern %q: %w", pattern, err)
+			}
+			if ok {
+				matched = append(matched, schema)
+				break
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("no schema matches %v", patterns)
+	}
+	return matched, nil
+}
@@ -0,0 +1,65 @@
+package inspector
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultWorkers はワーカー数が未指定の場合の並列数です。
+const DefaultWorkers = 4
+
+// ForEach は 0 から n-1 の各番号について fn を最大 workers 個のゴルーチンで並列に実行します。
+// いずれかの fn がエラーを返すと fn に渡すコンテキストをキャンセルして未着手の処理を打ち切り、最初のエラーを返します。
+// 出力順を入力順に保つため、fn は番号に対応するスライスの要素へ結果を書き込んでください。
+func ForEach(ctx context.Context, workers, n int, fn func(ctx context.Context, i int) error) error {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if workers > n {
+		workers = n
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	jobs := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := fn(ctx, i); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
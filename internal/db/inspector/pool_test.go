@@ -0,0 +1,139 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ワーカー数にかかわらず番号に対応する位置へ結果が書き込まれ、並列数が workers を超えないこと
+func TestForEachOrder(t *testing.T) {
+	tests := []struct {
+		workers, n int
+	}{
+		{workers: 1, n: 100},
+		{workers: 8, n: 100},
+		{workers: 0, n: 100},
+		{workers: 10, n: 3},
+		{workers: 4, n: 0},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("workers=%d/n=%d", tt.workers, tt.n), func(t *testing.T) {
+			limit := tt.workers
+			if limit <= 0 {
+				limit = DefaultWorkers
+			}
+			var running, maxRunning, calls int64
+			results := make([]int, tt.n)
+			err := ForEach(context.Background(), tt.workers, tt.n, func(ctx context.Context, i int) error {
+				atomic.AddInt64(&calls, 1)
+				r := atomic.AddInt64(&running, 1)
+				defer atomic.AddInt64(&running, -1)
+				for {
+					m := atomic.LoadInt64(&maxRunning)
+					if r <= m || atomic.CompareAndSwapInt64(&maxRunning, m, r) {
+						break
+					}
+				}
+				time.Sleep(time.Duration(i%3) * time.Millisecond)
+				results[i] = i * i
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if calls != int64(tt.n) {
+				t.Errorf("got %d calls, want %d", calls, tt.n)
+			}
+			if maxRunning > int64(limit) {
+				t.Errorf("got %d concurrent calls, want at most %d", maxRunning, limit)
+			}
+			for i, got := range results {
+				if got != i*i {
+					t.Fatalf("results[%d] = %d, want %d", i, got, i*i)
+				}
+			}
+		})
+	}
+}
+
+// 最初のエラーで残りの処理を打ち切り、そのエラーを返すこと
+func TestForEachFirstError(t *testing.T) {
+	errFirst := errors.New("first")
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			var calls int64
+			err := ForEach(context.Background(), workers, 100, func(ctx context.Context, i int) error {
+				atomic.AddInt64(&calls, 1)
+				if i == 0 {
+					return errFirst
+				}
+				// エラーによるキャンセルを待ってから、後発のエラーを返す
+				<-ctx.Done()
+				return fmt.Errorf("job %d: %w", i, ctx.Err())
+			})
+			if err != errFirst {
+				t.Errorf("got error %v, want %v", err, errFirst)
+			}
+			if calls > int64(workers) {
+				t.Errorf("got %d calls after the first error, want at most %d", calls, workers)
+			}
+		})
+	}
+}
+
+// 呼び出し元のコンテキストがキャンセルされると ctx.Err() を返すこと
+func TestForEachParentCanceled(t *testing.T) {
+	t.Run("before start", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var calls int64
+		err := ForEach(ctx, 4, 100, func(ctx context.Context, i int) error {
+			atomic.AddInt64(&calls, 1)
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %v, want %v", err, context.Canceled)
+		}
+		if calls != 0 {
+			t.Errorf("got %d calls, want 0", calls)
+		}
+	})
+
+	t.Run("while running", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var calls int64
+		err := ForEach(ctx, 4, 100, func(ctx context.Context, i int) error {
+			atomic.AddInt64(&calls, 1)
+			if i == 10 {
+				cancel()
+			}
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %v, want %v", err, context.Canceled)
+		}
+		if calls == 100 {
+			t.Errorf("all jobs ran after the context was canceled")
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := ForEach(ctx, 2, 100, func(ctx context.Context, i int) error {
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			return nil
+		})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
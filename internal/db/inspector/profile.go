@@ -0,0 +1,260 @@
+package inspector
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"export-db-info/internal/model/sql_model"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// プロファイリングの既定値です。
+const (
+	DefaultProfileSampleRows = 10000
+	DefaultProfileTopN       = 5
+	DefaultProfileTimeout    = 10 * time.Second
+)
+
+// maxProfileValueLength は最小値・最大値・出現回数の多い値として出力する値の最大の長さ（文字数）です。
+const maxProfileValueLength = 100
+
+// unprofiledTypes はプロファイリングの対象外とするデータ型です（値を文字列として比較・集計できないバイナリ・空間データ型）。
+var unprofiledTypes = []string{"blob", "binary", "bytea", "geometry", "geography", "point", "linestring", "polygon"}
+
+// Profiler はテーブルのデータをサンプリングしてカラムの値の傾向を調べられるSchemaInspectorです。
+type Profiler interface {
+	SchemaInspector
+	// Profile は dbInfos の各テーブルのカラムに値の傾向（sql_model.ColumnProfile）を設定します。
+	Profile(ctx context.Context, dbInfos []*sql_model.DB) error
+}
+
+// ProfileOptions はプロファイリングのサンプリングの上限です。
+type ProfileOptions struct {
+	SampleRows int           // テーブルごとにサンプリングする最大行数
+	TopN       int           // 出現回数の多い値を表示する数
+	Timeout    time.Duration // テーブルごとのサンプリングの時間の上限
+}
+
+// ProfileOptions は接続設定からプロファイリングの上限を返します。未指定の項目は既定値とします。
+func (cfg *Config) ProfileOptions() ProfileOptions {
+	opts := ProfileOptions{SampleRows: cfg.ProfileSampleRows, TopN: cfg.ProfileTopN, Timeout: cfg.ProfileTimeout}
+	if opts.SampleRows <= 0 {
+		opts.SampleRows = DefaultProfileSampleRows
+	}
+	if opts.TopN <= 0 {
+		opts.TopN = DefaultProfileTopN
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultProfileTimeout
+	}
+	return opts
+}
+
+// SampleQueryFunc はテーブルから columns の値を最大 limit 行取得するSELECT文を返します。
+// サンプリングの方法（TABLESAMPLE など）はDBエンジンごとに異なるため、各ドライバが組み立てます。
+type SampleQueryFunc func(table *sql_model.Table, columns []*sql_model.Column, limit int) string
+
+// ProfileTable はテーブルのデータをサンプリングし、各カラムの Profile を設定します。
+// テーブルごとに opts.Timeout を時間の上限とし、上限に達した場合はそれまでに取得できた行で集計します。
+func ProfileTable(ctx context.Context, db Querier, table *sql_model.Table, opts ProfileOptions, sampleQuery SampleQueryFunc) error {
+	var columns []*sql_model.Column
+	for _, col := range table.Columns {
+		if isProfiled(col) {
+			columns = append(columns, col)
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	qctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(qctx, sampleQuery(table, columns, opts.SampleRows))
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(qctx.Err(), context.DeadlineExceeded) {
+			// 1行も取得できないまま時間の上限に達した場合は調べない
+			return nil
+		}
+		return err
+	}
+	defer rows.Close()
+
+	stats := make([]*columnStats, len(columns))
+	for i := range stats {
+		stats[i] = &columnStats{counts: make(map[string]int64)}
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var sampled int64
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			stats[i].add(v)
+		}
+		sampled++
+	}
+	partial := false
+	if err := rows.Err(); err != nil {
+		if ctx.Err() != nil || !errors.Is(qctx.Err(), context.DeadlineExceeded) {
+			return err
+		}
+		partial = true
+	}
+	if sampled == 0 {
+		return nil
+	}
+
+	// サンプルがテーブル全体でない場合は、推定行数から異なる値の数を推定する
+	var tableRows int64
+	if (partial || sampled >= int64(opts.SampleRows)) && table.TableRows != nil && *table.TableRows > sampled {
+		tableRows = *table.TableRows
+	}
+	for i, col := range columns {
+		col.Profile = stats[i].profile(sampled, tableRows, opts.TopN)
+		col.Profile.IsPartial = partial
+	}
+	return nil
+}
+
+// isProfiled はカラムをプロファイリングの対象とするかどうかを返します。
+func isProfiled(col *sql_model.Column) bool {
+	columnType := strings.ToLower(col.Type)
+	for _, t := range unprofiledTypes {
+		if strings.Contains(columnType, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// columnStats はサンプリングした1つのカラムの値の集計です。
+type columnStats struct {
+	nulls       int64
+	nonNull     int64
+	totalLength int64
+	counts      map[string]int64
+	min, max    string
+}
+
+func (s *columnStats) add(v sql.NullString) {
+	if !v.Valid {
+		s.nulls++
+		return
+	}
+	if s.nonNull == 0 || compareValues(v.String, s.min) < 0 {
+		s.min = v.String
+	}
+	if s.nonNull == 0 || compareValues(v.String, s.max) > 0 {
+		s.max = v.String
+	}
+	s.nonNull++
+	s.totalLength += int64(utf8.RuneCountInString(v.String))
+	s.counts[v.String]++
+}
+
+// profile は集計結果から ColumnProfile を作成します。
+// tableRows が0でない場合、サンプルはテーブルの一部として異なる値の数を推定します。
+func (s *columnStats) profile(sampled, tableRows int64, topN int) *sql_model.ColumnProfile {
+	profile := &sql_model.ColumnProfile{
+		SampledRows:   sampled,
+		NullRatio:     float64(s.nulls) / float64(sampled),
+		DistinctCount: estimateDistinct(s.counts, s.nonNull, tableRows*s.nonNull/sampled),
+	}
+	if s.nonNull == 0 {
+		return profile
+	}
+	profile.Min = truncateValue(s.min)
+	profile.Max = truncateValue(s.max)
+	profile.AvgLength = float64(s.totalLength) / float64(s.nonNull)
+
+	// どの値も1回しか現れない場合（一意な値のカラムなど）は、出現回数の多い値を出さない
+	for value, count := range s.counts {
+		if count > 1 {
+			profile.TopValues = append(profile.TopValues, &sql_model.ValueCount{Value: value, Count: count})
+		}
+	}
+	sort.Slice(profile.TopValues, func(i, j int) bool {
+		a, b := profile.TopValues[i], profile.TopValues[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Value < b.Value
+	})
+	if len(profile.TopValues) > topN {
+		profile.TopValues = profile.TopValues[:topN]
+	}
+	for _, vc := range profile.TopValues {
+		vc.Value = truncateValue(vc.Value)
+	}
+	return profile
+}
+
+// estimateDistinct はサンプル内の値の出現回数から、テーブル全体（NULLを除いて total 行）の異なる値の数を推定します。
+// total が sampled 以下の場合（サンプルがテーブル全体の場合）はサンプル内の異なる値の数を返します。
+// 推定には、サンプル内で1回だけ現れた値を sqrt(total/sampled) 倍する GEE（Guaranteed-Error Estimator）を使います。
+func estimateDistinct(counts map[string]int64, sampled, total int64) int64 {
+	if total <= sampled || sampled == 0 {
+		return int64(len(counts))
+	}
+	var once, more int64
+	for _, c := range counts {
+		if c == 1 {
+			once++
+		} else {
+			more++
+		}
+	}
+	estimate := int64(math.Round(math.Sqrt(float64(total)/float64(sampled))*float64(once))) + more
+	if estimate > total {
+		estimate = total
+	}
+	return estimate
+}
+
+// compareValues は2つの値を、どちらも数値として解釈できる場合は数値として、どちらも解釈できない場合は文字列として比較します。
+// 数値と数値以外が混在するカラムでも値を読み取った順によらず最小値・最大値が決まるよう、数値は数値以外より小さいとみなします。
+func compareValues(a, b string) int {
+	x, okX := parseNumber(a)
+	y, okY := parseNumber(b)
+	switch {
+	case okX && okY:
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return strings.Compare(a, b)
+	case okX:
+		return -1
+	case okY:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// parseNumber は値を数値として解釈します。大小を比較できない NaN は数値とみなしません。
+func parseNumber(value string) (float64, bool) {
+	n, err := strconv.ParseFloat(value, 64)
+	return n, err == nil && !math.IsNaN(n)
+}
+
+// truncateValue は値を maxProfileValueLength 文字までに切り詰めます。
+func truncateValue(value string) string {
+	if utf8.RuneCountInString(value) <= maxProfileValueLength {
+		return value
+	}
+	return string([]rune(value)[:maxProfileValueLength]) + "…"
+}
@@ -0,0 +1,209 @@
+package inspector
+
+import (
+	"database/sql"
+	"export-db-info/internal/model/sql_model"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newColumnStats は values（nil はNULL）を順に集計します。
+func newColumnStats(values ...*string) *columnStats {
+	s := &columnStats{counts: make(map[string]int64)}
+	for _, v := range values {
+		if v == nil {
+			s.add(sql.NullString{})
+		} else {
+			s.add(sql.NullString{String: *v, Valid: true})
+		}
+	}
+	return s
+}
+
+// values は文字列を集計用の値の並びにします。
+func values(strs ...string) []*string {
+	var vs []*string
+	for i := range strs {
+		vs = append(vs, &strs[i])
+	}
+	return vs
+}
+
+// サンプル全体・一部のサンプル・すべてNULLのカラムの値の傾向を集計すること
+func TestColumnStatsProfile(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []*string
+		tableRows int64
+		topN      int
+		want      *sql_model.ColumnProfile
+	}{
+		{
+			name:   "full sample",
+			values: append(values("a", "bb", "a", "ccc"), nil),
+			topN:   5,
+			want: &sql_model.ColumnProfile{
+				SampledRows: 5, NullRatio: 0.2, DistinctCount: 3, Min: "a", Max: "ccc", AvgLength: 1.75,
+				TopValues: []*sql_model.ValueCount{{Value: "a", Count: 2}},
+			},
+		},
+		{
+			// 1回だけ現れた値 4 個を sqrt(600/6) 倍し、2回以上現れた値 1 個を足す
+			name:      "partial sample",
+			values:    values("x", "x", "1", "2", "3", "4"),
+			tableRows: 600,
+			topN:      5,
+			want: &sql_model.ColumnProfile{
+				SampledRows: 6, DistinctCount: 41, Min: "1", Max: "x", AvgLength: 1,
+				TopValues: []*sql_model.ValueCount{{Value: "x", Count: 2}},
+			},
+		},
+		{
+			// NULLを除いた行の割合でテーブル全体の行数を見積もる
+			name:      "partial sample with nulls",
+			values:    append(values("1", "2"), nil, nil),
+			tableRows: 16,
+			topN:      5,
+			want: &sql_model.ColumnProfile{
+				SampledRows: 4, NullRatio: 0.5, DistinctCount: 4, Min: "1", Max: "2", AvgLength: 1,
+			},
+		},
+		{
+			name:      "all null",
+			values:    []*string{nil, nil, nil},
+			tableRows: 1000,
+			topN:      5,
+			want:      &sql_model.ColumnProfile{SampledRows: 3, NullRatio: 1},
+		},
+		{
+			name:   "top values tie-break",
+			values: values("d", "c", "b", "a", "b", "c", "d", "b", "a", "e"),
+			topN:   3,
+			want: &sql_model.ColumnProfile{
+				SampledRows: 10, DistinctCount: 5, Min: "a", Max: "e", AvgLength: 1,
+				TopValues: []*sql_model.ValueCount{{Value: "b", Count: 3}, {Value: "a", Count: 2}, {Value: "c", Count: 2}},
+			},
+		},
+		{
+			name:   "truncated values",
+			values: values(strings.Repeat("あ", 101), strings.Repeat("あ", 101)),
+			topN:   5,
+			want: &sql_model.ColumnProfile{
+				SampledRows: 2, DistinctCount: 1,
+				Min: strings.Repeat("あ", 100) + "…", Max: strings.Repeat("あ", 100) + "…", AvgLength: 101,
+				TopValues: []*sql_model.ValueCount{{Value: strings.Repeat("あ", 100) + "…", Count: 2}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newColumnStats(tt.values...).profile(int64(len(tt.values)), tt.tableRows, tt.topN)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", *got, *tt.want)
+				for _, vc := range got.TopValues {
+					t.Logf("top value %q: %d", vc.Value, vc.Count)
+				}
+			}
+		})
+	}
+}
+
+// 数値と数値以外が混在していても、値を読み取った順によらず同じ最小値・最大値になること
+func TestColumnStatsMinMaxOrder(t *testing.T) {
+	inputs := []string{"10", "9", "5a", "abc", "-1.5", "1e3"}
+	var permute func(vs []string, n int)
+	permute = func(vs []string, n int) {
+		if n == 1 {
+			s := newColumnStats(values(vs...)...)
+			if s.min != "-1.5" || s.max != "abc" {
+				t.Errorf("order %q: got min %q max %q, want min %q max %q", vs, s.min, s.max, "-1.5", "abc")
+			}
+			return
+		}
+		for i := 0; i < n; i++ {
+			permute(vs, n-1)
+			if n%2 == 0 {
+				vs[i], vs[n-1] = vs[n-1], vs[i]
+			} else {
+				vs[0], vs[n-1] = vs[n-1], vs[0]
+			}
+		}
+	}
+	permute(inputs, len(inputs))
+}
+
+// 数値同士は数値として、それ以外は文字列として比較し、数値を数値以外より小さいとみなすこと
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"9", "10", -1},
+		{"-1.5", "-2", 1},
+		{"1e3", "999", 1},
+		{"1", "1", 0},
+		{"1.0", "1", 1},
+		{"abc", "abd", -1},
+		{"10", "9a", -1},
+		{"9a", "10", 1},
+		{"NaN", "1", 1},
+		{"NaN", "Nb", -1},
+		{"", "0", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := compareValues(tt.a, tt.b); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// サンプルがテーブル全体の場合は数え、一部の場合は推定し、推定値をテーブルの行数で打ち切ること
+func TestEstimateDistinct(t *testing.T) {
+	tests := []struct {
+		name           string
+		counts         map[string]int64
+		sampled, total int64
+		want           int64
+	}{
+		{name: "empty", counts: map[string]int64{}, sampled: 0, total: 0, want: 0},
+		{name: "full sample", counts: map[string]int64{"a": 1, "b": 3}, sampled: 4, total: 4, want: 2},
+		{name: "total below sampled", counts: map[string]int64{"a": 1, "b": 3}, sampled: 4, total: 2, want: 2},
+		{name: "only repeated values", counts: map[string]int64{"a": 50, "b": 50}, sampled: 100, total: 10000, want: 2},
+		{name: "only unique values", counts: map[string]int64{"a": 1, "b": 1, "c": 1, "d": 1}, sampled: 4, total: 100, want: 20},
+		{name: "rounded", counts: map[string]int64{"a": 1, "b": 1, "c": 2}, sampled: 4, total: 8, want: 4},
+		{name: "capped at total", counts: map[string]int64{"a": 1, "b": 1, "c": 1, "d": 1}, sampled: 2, total: 3, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateDistinct(tt.counts, tt.sampled, tt.total); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// 文字数で切り詰め、マルチバイト文字を途中で切らないこと
+func TestTruncateValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "short", value: "abc", want: "abc"},
+		{name: "ascii at limit", value: strings.Repeat("a", 100), want: strings.Repeat("a", 100)},
+		{name: "ascii over limit", value: strings.Repeat("a", 101), want: strings.Repeat("a", 100) + "…"},
+		{name: "multibyte at limit", value: strings.Repeat("あ", 100), want: strings.Repeat("あ", 100)},
+		{name: "multibyte over limit", value: strings.Repeat("あ", 150), want: strings.Repeat("あ", 100) + "…"},
+		{name: "emoji", value: strings.Repeat("a", 99) + "😀😀", want: strings.Repeat("a", 99) + "😀…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateValue(tt.value); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package inspector
+
+import (
+	"context"
+	"database/sql"
+	"log"
+)
+
+// Querier はメタデータの問い合わせに使う接続です。*sql.DB・*sql.Conn・*sql.Tx が該当します。
+// 一貫性のあるスナップショットから取得する場合、各ドライバは1つの接続上のトランザクションを Querier として使います。
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// QueryWorkers は問い合わせを並列に実行するワーカー数を返します。
+// スナップショットを複数の接続で共有できないドライバ（MySQL・SQLite）向けで、
+// スナップショットから取得する場合は1つの接続で順に問い合わせるため 1 を返します。
+func (cfg *Config) QueryWorkers() int {
+	if cfg.Snapshot {
+		if cfg.Workers > 1 {
+			log.Printf("DB_WORKERS=%d is ignored because DB_SNAPSHOT is enabled; set DB_SNAPSHOT=false to query in parallel", cfg.Workers)
+		}
+		return 1
+	}
+	return cfg.Workers
+}
+
+// QuerierPool は並列の問い合わせに使う接続の集まりです。
+// スナップショットから取得する場合は、同じスナップショットを共有するトランザクションを接続ごとに持ちます。
+type QuerierPool struct {
+	primary  Querier
+	queriers chan Querier
+}
+
+// NewQuerierPool は queriers を貸し出す QuerierPool を作成します。queriers は1つ以上指定してください。
+func NewQuerierPool(queriers ...Querier) *QuerierPool {
+	p := &QuerierPool{primary: queriers[0], queriers: make(chan Querier, len(queriers))}
+	for _, q := range queriers {
+		p.queriers <- q
+	}
+	return p
+}
+
+// Primary は並列でない問い合わせに使う接続を返します。Do の実行中は使わないでください。
+func (p *QuerierPool) Primary() Querier {
+	return p.primary
+}
+
+// Size は接続の数（並列に問い合わせられる数）を返します。
+func (p *QuerierPool) Size() int {
+	return cap(p.queriers)
+}
+
+// Do は空いている接続を1つ借りて fn を実行します。空いている接続がない場合は返されるまで待ちます。
+func (p *QuerierPool) Do(ctx context.Context, fn func(q Querier) error) error {
+	select {
+	case q := <-p.queriers:
+		defer func() { p.queriers <- q }()
+		return fn(q)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
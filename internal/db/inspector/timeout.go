@@ -0,0 +1,22 @@
+package inspector
+
+import (
+	"context"
+	"time"
+)
+
+type queryTimeoutKey struct{}
+
+// WithQueryTimeout は問い合わせごとのタイムアウトを設定したコンテキストを返します。0以下の場合はタイムアウトを設定しません。
+func WithQueryTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, timeout)
+}
+
+// QueryTimeout は WithQueryTimeout で設定したタイムアウトを適用したコンテキストを返します。
+// 各ドライバはメタデータの問い合わせごとに呼び出し、結果を読み終えた後に cancel を呼び出してください。
+func QueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout, _ := ctx.Value(queryTimeoutKey{}).(time.Duration); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
@@ -0,0 +1,56 @@
+package mysql_internal
+
+import (
+	"context"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+)
+
+// getGrants はデータベースに対する権限を information_schema の USER_PRIVILEGES・SCHEMA_PRIVILEGES・
+// TABLE_PRIVILEGES・COLUMN_PRIVILEGES から取得します。
+// グローバル権限はすべてのデータベースに及ぶため含めます。データベース単位の権限はワイルドカードで付与されている場合があります。
+// 接続ユーザーが mysql データベースの SELECT 権限を持たない場合、取得できるのは接続ユーザー自身の権限のみです。
+func getGrants(ctx context.Context, db inspector.Querier, dbName string) ([]*sql_model.Grant, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+    SELECT GRANTEE, 'GLOBAL' AS LEVEL, 1 AS LEVEL_ORDER, '' AS TABLE_SCHEMA, '' AS TABLE_NAME, '' AS COLUMN_NAME,
+           PRIVILEGE_TYPE, IS_GRANTABLE
+    FROM information_schema.USER_PRIVILEGES
+    WHERE PRIVILEGE_TYPE <> 'USAGE'
+    UNION ALL
+    SELECT GRANTEE, 'SCHEMA', 2, TABLE_SCHEMA, '', '', PRIVILEGE_TYPE, IS_GRANTABLE
+    FROM information_schema.SCHEMA_PRIVILEGES
+    WHERE ? LIKE TABLE_SCHEMA
+    UNION ALL
+    SELECT GRANTEE, 'TABLE', 3, TABLE_SCHEMA, TABLE_NAME, '', PRIVILEGE_TYPE, IS_GRANTABLE
+    FROM information_schema.TABLE_PRIVILEGES
+    WHERE TABLE_SCHEMA = ?
+    UNION ALL
+    SELECT GRANTEE, 'COLUMN', 4, TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, PRIVILEGE_TYPE, IS_GRANTABLE
+    FROM information_schema.COLUMN_PRIVILEGES
+    WHERE TABLE_SCHEMA = ?
+    ORDER BY GRANTEE, LEVEL_ORDER, TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, PRIVILEGE_TYPE
+    `
+	rows, err := db.QueryContext(ctx, query, dbName, dbName, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var grants []*sql_model.Grant
+	for rows.Next() {
+		grant := new(sql_model.Grant)
+		var levelOrder int
+		var isGrantable string
+		err := rows.Scan(&grant.Grantee, &grant.Level, &levelOrder, &grant.Schema, &grant.Table, &grant.Column,
+			&grant.Privilege, &isGrantable)
+		if err != nil {
+			return nil, err
+		}
+		grant.IsGrantable = isGrantable == "YES"
+		grants = append(grants, grant)
+	}
+	return grants, rows.Err()
+}
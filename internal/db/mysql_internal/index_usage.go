@@ -0,0 +1,238 @@
+package mysql_internal
+
+import (
+	"context"
+	"database/sql"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// getIndexReports は削除を検討できるインデックスを performance_schema と sys スキーマから調べます。
+// 主キー・ユニークインデックスと外部キーに使われるインデックスは制約のために必要なため、未使用・冗長のどちらの場合も除きます。
+// 同じインデックスが複数の理由（または複数の包含するインデックス）で該当する場合は最初の1件のみを返します。
+// 読み取り回数はサーバーの起動（または統計のリセット）からの累計のため、起動直後は実際には使われているインデックスも含まれます。
+// performance_schema やテーブルI/Oの計測が無効の場合は、すべてのインデックスが未使用に見えるため未使用のインデックスは調べません。
+func getIndexReports(ctx context.Context, db inspector.Querier, dbName string, tables []*sql_model.Table) ([]*sql_model.IndexReport, error) {
+	instrumented, err := isIndexUsageInstrumented(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("could not read performance_schema settings: %w", err)
+	}
+
+	var unused []*sql_model.IndexReport
+	readCounts := make(map[string]int64)
+	if instrumented {
+		if readCounts, err = getIndexReadCounts(ctx, db, dbName); err != nil {
+			return nil, fmt.Errorf("could not read performance_schema (DB_INDEX_USAGE requires performance_schema to be enabled): %w", err)
+		}
+		if unused, err = getUnusedIndexes(ctx, db, dbName); err != nil {
+			return nil, fmt.Errorf("could not read sys.schema_unused_indexes (DB_INDEX_USAGE requires the sys schema): %w", err)
+		}
+	} else {
+		log.Printf("%s: skipping unused index report because performance_schema or its table I/O instrumentation "+
+			"(wait/io/table/sql/handler, global_instrumentation) is disabled", dbName)
+	}
+	redundant, err := getRedundantIndexes(ctx, db, dbName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read sys.schema_redundant_indexes (DB_INDEX_USAGE requires the sys schema): %w", err)
+	}
+
+	tableByName := make(map[string]*sql_model.Table, len(tables))
+	for _, table := range tables {
+		tableByName[table.Name] = table
+	}
+
+	var reports []*sql_model.IndexReport
+	seen := make(map[string]bool)
+	for _, report := range append(unused, redundant...) {
+		key := report.Table + "." + report.Index
+		if seen[key] {
+			continue
+		}
+		table := tableByName[report.Table]
+		if table == nil {
+			continue
+		}
+		idx := findIndex(table.Indexes, report.Index)
+		if idx == nil || idx.IsPrimary || idx.IsUnique || supportsForeignKey(idx, table.ForeignKeys) {
+			continue
+		}
+		seen[key] = true
+		if report.Columns == "" {
+			report.Columns = indexColumnNames(idx)
+		}
+		if report.DropStatement == "" {
+			report.DropStatement = dropIndexStatement(dbName, report.Table, report.Index)
+		}
+		if count, ok := readCounts[key]; ok {
+			report.ReadCount = &count
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// isIndexUsageInstrumented は performance_schema が有効で、インデックスごとの読み取り回数を計測する設定
+// （計測器 wait/io/table/sql/handler とコンシューマ global_instrumentation）が有効かどうかを返します。
+func isIndexUsageInstrumented(ctx context.Context, db inspector.Querier) (bool, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var enabled bool
+	if err := db.QueryRowContext(ctx, "SELECT @@performance_schema").Scan(&enabled); err != nil || !enabled {
+		return false, err
+	}
+
+	var instruments, consumers int
+	err := db.QueryRowContext(ctx, `
+    SELECT
+        (SELECT COUNT(*) FROM performance_schema.setup_instruments
+         WHERE NAME = 'wait/io/table/sql/handler' AND ENABLED = 'YES'),
+        (SELECT COUNT(*) FROM performance_schema.setup_consumers
+         WHERE NAME = 'global_instrumentation' AND ENABLED = 'YES')
+    `).Scan(&instruments, &consumers)
+	if err != nil {
+		return false, err
+	}
+	return instruments > 0 && consumers > 0, nil
+}
+
+// getIndexReadCounts はインデックスごとの読み取り回数を「テーブル名.インデックス名」をキーとして取得します。
+func getIndexReadCounts(ctx context.Context, db inspector.Querier, dbName string) (map[string]int64, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
+    SELECT OBJECT_NAME, INDEX_NAME, COUNT_READ
+    FROM performance_schema.table_io_waits_summary_by_index_usage
+    WHERE OBJECT_SCHEMA = ? AND OBJECT_TYPE = 'TABLE' AND INDEX_NAME IS NOT NULL
+    `, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	readCounts := make(map[string]int64)
+	for rows.Next() {
+		var tableName, indexName string
+		var count int64
+		if err := rows.Scan(&tableName, &indexName, &count); err != nil {
+			return nil, err
+		}
+		readCounts[tableName+"."+indexName] = count
+	}
+	return readCounts, rows.Err()
+}
+
+// getUnusedIndexes はサーバーの起動後に一度も使われていないインデックスを取得します。
+func getUnusedIndexes(ctx context.Context, db inspector.Querier, dbName string) ([]*sql_model.IndexReport, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
+    SELECT object_name, index_name
+    FROM sys.schema_unused_indexes
+    WHERE object_schema = ?
+    ORDER BY object_name, index_name
+    `, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []*sql_model.IndexReport
+	for rows.Next() {
+		report := &sql_model.IndexReport{Reason: "UNUSED"}
+		if err := rows.Scan(&report.Table, &report.Index); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// getRedundantIndexes は他のインデックスに包含される冗長なインデックスを、削除するSQLとともに取得します。
+func getRedundantIndexes(ctx context.Context, db inspector.Querier, dbName string) ([]*sql_model.IndexReport, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
+    SELECT table_name, redundant_index_name, redundant_index_columns,
+           dominant_index_name, dominant_index_columns, sql_drop_index
+    FROM sys.schema_redundant_indexes
+    WHERE table_schema = ?
+    ORDER BY table_name, redundant_index_name
+    `, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []*sql_model.IndexReport
+	for rows.Next() {
+		report := &sql_model.IndexReport{Reason: "REDUNDANT"}
+		var dropStatement sql.NullString
+		err := rows.Scan(&report.Table, &report.Index, &report.Columns,
+			&report.DominantIndex, &report.DominantColumns, &dropStatement)
+		if err != nil {
+			return nil, err
+		}
+		report.DropStatement = dropStatement.String
+		if report.DropStatement == "" {
+			report.DropStatement = dropIndexStatement(dbName, report.Table, report.Index)
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// supportsForeignKey はインデックスの先頭のカラムが外部キーのカラムと一致するかどうかを返します。
+// InnoDB では外部キーのカラムにインデックスが必要なため、このようなインデックスは削除できない場合があります。
+func supportsForeignKey(idx *sql_model.Index, foreignKeys []*sql_model.ForeignKey) bool {
+	for _, fk := range foreignKeys {
+		if len(fk.Columns) > len(idx.Columns) {
+			continue
+		}
+		matched := true
+		for i, name := range fk.Columns {
+			if idx.Columns[i].Name != name {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// findIndex はインデックスのスライスから名前が一致するものを返します。見つからない場合はnilを返します。
+func findIndex(indexes []*sql_model.Index, name string) *sql_model.Index {
+	for _, idx := range indexes {
+		if idx.Name == name {
+			return idx
+		}
+	}
+	return nil
+}
+
+// indexColumnNames はインデックスを構成するカラム名（関数インデックスの場合は式）をカンマ区切りで返します。
+func indexColumnNames(idx *sql_model.Index) string {
+	var names []string
+	for _, part := range idx.Columns {
+		if part.Name != "" {
+			names = append(names, part.Name)
+		} else {
+			names = append(names, part.Expression)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// dropIndexStatement はインデックスを削除する ALTER TABLE 文を返します。
+func dropIndexStatement(dbName, tableName, indexName string) string {
+	return "ALTER TABLE " + quoteIdentifier(dbName) + "." + quoteIdentifier(tableName) + " DROP INDEX " + quoteIdentifier(indexName)
+}
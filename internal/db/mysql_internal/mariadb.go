@@ -0,0 +1,194 @@
+package mysql_internal
+
+import (
+	"context"
+	"errors"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// isMariaDB は接続先がMariaDBかどうかを返します。MariaDBの VERSION() は「10.11.6-MariaDB」の形式です。
+func isMariaDB(ctx context.Context, db inspector.Querier) (bool, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		return false, err
+	}
+	return strings.Contains(strings.ToLower(version), "mariadb"), nil
+}
+
+// mariaDBDefault は MariaDB（10.2.7 以降）の COLUMN_DEFAULT の値を MySQL と同じ形に変換し、式かどうかとともに返します。
+// MariaDB では DEFAULT NULL が文字列の NULL、文字列のデフォルト値がクォートで囲んだ形（'abc'）で返され、
+// 式のデフォルト値はクォートなしで返されます（EXTRA に DEFAULT_GENERATED は付きません）。
+func mariaDBDefault(value string) (*string, bool) {
+	switch {
+	case value == "NULL":
+		return nil, false
+	case len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'"):
+		literal := strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+		return &literal, false
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return &value, err != nil
+}
+
+// periodPattern は SHOW CREATE TABLE の PERIOD FOR 句です。
+var periodPattern = regexp.MustCompile("(?i)PERIOD\\s+FOR\\s+`?(\\w+)`?\\s*\\(\\s*`((?:[^`]|``)+)`\\s*,\\s*`((?:[^`]|``)+)`\\s*\\)")
+
+// getPeriods はシステムバージョニングされたテーブルの期間を、テーブル名ごとに取得します。
+// information_schema に期間の情報がないバージョンがあるため、SHOW CREATE TABLE の PERIOD FOR 句から読み取ります。
+// 開始・終了カラムを省略したシステムバージョニングでは、暗黙のカラム ROW_START・ROW_END を期間とします。
+func getPeriods(ctx context.Context, db inspector.Querier, dbName string, tableNames []string) (map[string][]*sql_model.Period, error) {
+	periods := make(map[string][]*sql_model.Period)
+	for _, tableName := range tableNames {
+		definition, err := showCreateTable(ctx, db, dbName, tableName)
+		if err != nil {
+			return nil, err
+		}
+
+		hasSystemTime := false
+		for _, m := range periodPattern.FindAllStringSubmatch(definition, -1) {
+			name := m[1]
+			if strings.EqualFold(name, "SYSTEM_TIME") {
+				name = "SYSTEM_TIME"
+				hasSystemTime = true
+			}
+			periods[tableName] = append(periods[tableName], &sql_model.Period{
+				Name:        name,
+				StartColumn: strings.ReplaceAll(m[2], "``", "`"),
+				EndColumn:   strings.ReplaceAll(m[3], "``", "`"),
+			})
+		}
+		if !hasSystemTime {
+			periods[tableName] = append([]*sql_model.Period{{Name: "SYSTEM_TIME", StartColumn: "ROW_START", EndColumn: "ROW_END"}}, periods[tableName]...)
+		}
+	}
+	return periods, nil
+}
+
+// showCreateTable はテーブルの CREATE TABLE 文を取得します。
+func showCreateTable(ctx context.Context, db inspector.Querier, dbName, tableName string) (string, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var name, definition string
+	query := "SHOW CREATE TABLE " + quoteIdentifier(dbName) + "." + quoteIdentifier(tableName)
+	if err := db.QueryRowContext(ctx, query).Scan(&name, &definition); err != nil {
+		return "", err
+	}
+	return definition, nil
+}
+
+// quoteIdentifier は識別子をバッククォートで囲みます。
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// jsonValidPattern はMariaDBがJSON型のカラムに自動で付けるCHECK制約の条件式です。
+var jsonValidPattern = regexp.MustCompile("^json_valid\\(`((?:[^`]|``)+)`\\)$")
+
+// applyJSONAliases はMariaDBのJSON型のカラムをJSON型として扱います。
+// MariaDBのJSON型は LONGTEXT の別名で、カラム名を制約名とする json_valid() のCHECK制約が自動で付くため、
+// その制約を持つ LONGTEXT のカラムをJSON型とし、自動で付いた制約はCHECK制約の一覧から除きます。
+func applyJSONAliases(table *sql_model.Table) {
+	var checks []*sql_model.Check
+	for _, check := range table.Checks {
+		m := jsonValidPattern.FindStringSubmatch(check.Expression)
+		if m == nil || check.Name != strings.ReplaceAll(m[1], "``", "`") {
+			checks = append(checks, check)
+			continue
+		}
+		col := findColumn(table.Columns, check.Name)
+		if col == nil || !strings.EqualFold(col.Type, "longtext") {
+			checks = append(checks, check)
+			continue
+		}
+		col.Type = "json"
+		col.CharacterSet, col.Collation = "", ""
+	}
+	table.Checks = checks
+}
+
+// getSequences はMariaDBのシーケンスを取得します。
+// シーケンスは information_schema.TABLES に TABLE_TYPE = 'SEQUENCE' のテーブルとして現れるため、
+// 一覧を取得した後、シーケンスごとに現在の設定を読み取ります。
+func getSequences(ctx context.Context, db inspector.Querier, dbName string) ([]*sql_model.Sequence, error) {
+	names, err := getSequenceNames(ctx, db, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	var sequences []*sql_model.Sequence
+	for _, name := range names {
+		sequence, err := getSequence(ctx, db, dbName, name)
+		if err != nil {
+			return nil, err
+		}
+		sequences = append(sequences, sequence)
+	}
+	return sequences, nil
+}
+
+func getSequenceNames(ctx context.Context, db inspector.Querier, dbName string) ([]string, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
+    SELECT TABLE_NAME
+    FROM information_schema.TABLES
+    WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'SEQUENCE'
+    ORDER BY TABLE_NAME
+    `, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// getSequence はシーケンスの設定を取得します。
+// 値は符号なしの BIGINT の場合があるため文字列で読み取り、int64 に収まらない値は int64 の範囲に丸めます。
+func getSequence(ctx context.Context, db inspector.Querier, dbName, name string) (*sql_model.Sequence, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var start, increment, minValue, maxValue string
+	var cycle bool
+	query := "SELECT start_value, increment, minimum_value, maximum_value, cycle_option FROM " +
+		quoteIdentifier(dbName) + "." + quoteIdentifier(name)
+	if err := db.QueryRowContext(ctx, query).Scan(&start, &increment, &minValue, &maxValue, &cycle); err != nil {
+		return nil, err
+	}
+
+	sequence := &sql_model.Sequence{Schema: dbName, Name: name, DataType: "bigint", Cycle: cycle}
+	for _, v := range []struct {
+		value string
+		dest  *int64
+	}{
+		{start, &sequence.Start},
+		{increment, &sequence.Increment},
+		{minValue, &sequence.MinValue},
+		{maxValue, &sequence.MaxValue},
+	} {
+		n, err := strconv.ParseInt(v.value, 10, 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return nil, err
+		}
+		*v.dest = n
+	}
+	return sequence, nil
+}
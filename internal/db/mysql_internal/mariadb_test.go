@@ -0,0 +1,33 @@
+package mysql_internal
+
+import "testing"
+
+func TestMariaDBDefault(t *testing.T) {
+	for _, tt := range []struct {
+		value        string
+		want         string
+		isNull       bool
+		isExpression bool
+	}{
+		{value: "NULL", isNull: true},
+		{value: "'NULL'", want: "NULL"},
+		{value: "'abc'", want: "abc"},
+		{value: "'it''s'", want: "it's"},
+		{value: "''", want: ""},
+		{value: "0", want: "0"},
+		{value: "-1.5", want: "-1.5"},
+		{value: "uuid()", want: "uuid()", isExpression: true},
+		{value: "current_timestamp()", want: "current_timestamp()", isExpression: true},
+	} {
+		got, isExpression := mariaDBDefault(tt.value)
+		if tt.isNull {
+			if got != nil {
+				t.Errorf("mariaDBDefault(%q) = %q, want nil", tt.value, *got)
+			}
+			continue
+		}
+		if got == nil || *got != tt.want || isExpression != tt.isExpression {
+			t.Errorf("mariaDBDefault(%q) = %v, %t, want %q, %t", tt.value, got, isExpression, tt.want, tt.isExpression)
+		}
+	}
+}
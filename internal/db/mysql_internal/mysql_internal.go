@@ -1,191 +1,905 @@
 package mysql_internal
 
 import (
+	"context"
 	"database/sql"
+	"export-db-info/internal/db/inspector"
 	"export-db-info/internal/model/sql_model"
 	"export-db-info/pkg/db/mysql"
-	"os"
+	"fmt"
+	"strings"
 )
 
-var (
-	dbHost     = os.Getenv("DB_HOST")
-	dbPort     = os.Getenv("DB_PORT")
-	dbName     = os.Getenv("DB_DATABASE")
-	dbUser     = os.Getenv("DB_USERNAME")
-	dbPassword = os.Getenv("DB_PASSWORD")
-)
+func init() {
+	inspector.Register("mysql", NewInspector)
+}
 
-func GetDatabaseInfo() (*sql_model.DB, error) {
-	// データベースに接続
-	db, err := mysql.Connect(dbUser, dbPassword, dbHost, dbPort, dbName)
+// Inspector はMySQLのinformation_schemaからスキーマ情報を取得します。
+type Inspector struct {
+	cfg    *inspector.Config
+	filter *inspector.Filter // テーブル単位の問い合わせの前に対象のテーブルを絞り込む
+}
+
+// NewInspector は接続設定からMySQL用のSchemaInspectorを生成します。
+func NewInspector(cfg *inspector.Config) (inspector.SchemaInspector, error) {
+	filter, err := inspector.NewFilter(cfg)
 	if err != nil {
 		return nil, err
 	}
+	return &Inspector{cfg: cfg, filter: filter}, nil
+}
 
-	// 各テーブルとそのカラム情報を取得
-	tables, err := getTables(db)
+// Inspect はデータベースに接続し、テーブルとカラムの情報を取得します。
+func (i *Inspector) Inspect(ctx context.Context) (*sql_model.DB, error) {
+	db, err := i.connect(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer db.Close()
 
-	return &sql_model.DB{Name: dbName, Tables: tables}, nil
+	if err := i.checkReadOnlyUser(ctx, db, []string{i.cfg.Database}); err != nil {
+		return nil, err
+	}
+	opts, err := i.schemaOptions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	q, end, err := i.begin(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	return inspectSchema(ctx, q, i.cfg.Database, opts)
 }
 
-func getTables(db *sql.DB) ([]*sql_model.Table, error) {
-	var tables []*sql_model.Table
+// InspectSchemas はデータベースに接続し、patterns に一致するデータベースごとにテーブルとカラムの情報を取得します。
+// スナップショットから取得する場合は、すべてのデータベースを同じトランザクション内で取得します。
+func (i *Inspector) InspectSchemas(ctx context.Context, patterns []string) ([]*sql_model.DB, error) {
+	db, err := i.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
 
-	// テーブル一覧の取得
-	rows, err := db.Query("SHOW TABLES")
+	schemas, err := listSchemas(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	schemas, err = inspector.MatchSchemas(schemas, patterns)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var tableName string
-		err := rows.Scan(&tableName)
-		if err != nil {
-			return nil, err
-		}
+	if err := i.checkReadOnlyUser(ctx, db, schemas); err != nil {
+		return nil, err
+	}
+	opts, err := i.schemaOptions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	q, end, err := i.begin(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
 
-		// カラム情報の取得
-		columns, err := getColumns(db, tableName)
+	var dbs []*sql_model.DB
+	for _, schema := range schemas {
+		dbInfo, err := inspectSchema(ctx, q, schema, opts)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%s: %w", schema, err)
 		}
+		dbs = append(dbs, dbInfo)
+	}
+	return dbs, nil
+}
 
-		tables = append(tables, &sql_model.Table{Name: tableName, Columns: columns})
+// connect はデータベースに接続し、疎通を確認します。
+func (i *Inspector) connect(ctx context.Context) (*sql.DB, error) {
+	opts := mysql.Options{
+		Username: i.cfg.Username,
+		Password: i.cfg.Password,
+		Host:     i.cfg.Host,
+		Port:     i.cfg.Port,
+		Database: i.cfg.Database,
+		Socket:   i.cfg.Socket,
+		Params:   i.cfg.Params,
+		DSN:      i.cfg.DSN,
+	}
+	if i.cfg.UseTLS() {
+		opts.TLS = &mysql.TLSOptions{
+			CAFile:     i.cfg.TLSCA,
+			CertFile:   i.cfg.TLSCert,
+			KeyFile:    i.cfg.TLSKey,
+			SkipVerify: i.cfg.TLSSkipVerify,
+		}
 	}
 
-	return tables, nil
+	db, err := mysql.Connect(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
 }
 
-func getColumns(db *sql.DB, tableName string) ([]*sql_model.Column, error) {
-	var columns []*sql_model.Column
+// systemSchemas はスキーマごとの取得の対象外とするシステムデータベースです。
+var systemSchemas = []string{"information_schema", "mysql", "performance_schema", "sys"}
+
+// listSchemas はシステムデータベースを除くデータベースの一覧を取得します。
+func listSchemas(ctx context.Context, db inspector.Querier) ([]string, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
 
-	// カラム情報の取得
 	query := `
-    SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, COLUMN_COMMENT, 
-           COLUMN_KEY
-    FROM information_schema.COLUMNS 
-    WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
-    ORDER BY ORDINAL_POSITION
+    SELECT SCHEMA_NAME
+    FROM information_schema.SCHEMATA
+    WHERE SCHEMA_NAME NOT IN (?, ?, ?, ?)
+    ORDER BY SCHEMA_NAME
     `
-	rows, err := db.Query(query, dbName, tableName)
+	args := make([]interface{}, len(systemSchemas))
+	for i, schema := range systemSchemas {
+		args[i] = schema
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	var schemas []string
 	for rows.Next() {
-		col := new(sql_model.Column)
-		var isNullable, columnKey string
-		var defaultVal sql.NullString
-		err := rows.Scan(&col.Name, &col.Type, &isNullable, &defaultVal, &col.Comment, &columnKey)
-		if err != nil {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
 			return nil, err
 		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, rows.Err()
+}
 
-		// sql.NullStringの値をチェック
-		if defaultVal.Valid {
-			col.Default = defaultVal.String
-		} else {
-			col.Default = "NULL" // NULLの場合、デフォルト値を空文字列に設定
-		}
-		col.IsNullable = isNullable == "YES"
-		col.IsPrimaryKey = columnKey == "PRI"
-		// ユニーク制約の確認（データベース固有のクエリが必要）
-		col.IsUnique, err = checkColumnIsUnique(db, tableName, col.Name)
+// schemaOptions は inspectSchema で取得する情報の指定です。
+type schemaOptions struct {
+	workers    int               // テーブル単位の情報を並列に取得するワーカー数
+	mariaDB    bool              // MariaDB 固有のシーケンス・システムバージョニング・JSON型を取得するかどうか
+	grants     bool              // データベースに対する権限を取得するかどうか
+	indexUsage bool              // 使われていない・冗長なインデックスを調べるかどうか
+	filter     *inspector.Filter // 取得するテーブル（nil の場合はすべて）
+}
+
+// schemaOptions は設定と接続先のサーバーの種類から inspectSchema で取得する情報を決めます。
+func (i *Inspector) schemaOptions(ctx context.Context, db inspector.Querier) (schemaOptions, error) {
+	mariaDB, err := isMariaDB(ctx, db)
+	if err != nil {
+		return schemaOptions{}, err
+	}
+	return schemaOptions{
+		workers:    i.cfg.QueryWorkers(),
+		mariaDB:    mariaDB,
+		grants:     i.cfg.Grants,
+		indexUsage: i.cfg.IndexUsage,
+		filter:     i.filter,
+	}, nil
+}
+
+// inspectSchema は1つのデータベースのテーブル・ビュー・シーケンス・ルーチン・イベントの情報を取得します。
+func inspectSchema(ctx context.Context, db inspector.Querier, dbName string, opts schemaOptions) (*sql_model.DB, error) {
+	// 各テーブル・ビューとそのカラム情報を取得
+	tables, views, err := getTables(ctx, db, dbName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// シーケンスを取得（MariaDB のみ）
+	var sequences []*sql_model.Sequence
+	if opts.mariaDB {
+		sequences, err = getSequences(ctx, db, dbName)
 		if err != nil {
 			return nil, err
 		}
-		// インデックスの存在確認（データベース固有のクエリが必要）
-		col.IsIndexed, err = checkColumnIsIndexed(db, tableName, col.Name)
+	}
+
+	// ストアドプロシージャ・ストアドファンクションとイベントを取得
+	routines, err := getRoutines(ctx, db, dbName)
+	if err != nil {
+		return nil, err
+	}
+	events, err := getEvents(ctx, db, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	dbInfo := &sql_model.DB{Name: dbName, Tables: tables, Views: views, Sequences: sequences, Routines: routines, Events: events}
+
+	// 権限を取得（DB_GRANTS を指定した場合のみ）
+	if opts.grants {
+		dbInfo.Grants, err = getGrants(ctx, db, dbName)
 		if err != nil {
 			return nil, err
 		}
-		// 外部キーの存在確認（データベース固有のクエリが必要）
-		col.IsForeign, err = checkColumnIsForeignKey(db, tableName, col.Name)
+	}
+
+	// 使われていない・冗長なインデックスを調べる（DB_INDEX_USAGE を指定した場合のみ）
+	if opts.indexUsage {
+		dbInfo.IndexReports, err = getIndexReports(ctx, db, dbName, tables)
 		if err != nil {
 			return nil, err
 		}
+	}
+	return dbInfo, nil
+}
+
+// getTables はスキーマ内のテーブル・ビューの情報を取得します。
+// information_schema への問い合わせはテーブル数・カラム数によらずスキーマ単位で一定回数とし、取得結果をメモリ上で結合します。
+// MariaDB のシーケンスは TABLES にテーブルとして現れるため除き、システムバージョニングされたテーブルの期間は別途取得します。
+// opts.filter で除外したテーブルは結果に含めず、SHOW CREATE TABLE などのテーブル単位の問い合わせも行いません。
+func getTables(ctx context.Context, db inspector.Querier, dbName string, opts schemaOptions) ([]*sql_model.Table, []*sql_model.View, error) {
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var tables []*sql_model.Table
+	var versionedTables []string
+
+	// テーブル一覧とテーブル単位の情報の取得
+	query := `
+    SELECT TABLE_NAME, TABLE_TYPE, COALESCE(TABLE_COMMENT, ''), COALESCE(ENGINE, ''), COALESCE(TABLE_COLLATION, ''),
+           COALESCE(ROW_FORMAT, ''), AUTO_INCREMENT, CREATE_TIME, UPDATE_TIME,
+           TABLE_ROWS, DATA_LENGTH, INDEX_LENGTH, DATA_FREE
+    FROM information_schema.TABLES
+    WHERE TABLE_SCHEMA = ? AND TABLE_TYPE NOT IN ('VIEW', 'SEQUENCE')
+    ORDER BY TABLE_NAME
+    `
+	rows, err := db.QueryContext(qctx, query, dbName)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		table := new(sql_model.Table)
+		var autoIncrement, tableRows, dataLength, indexLength, dataFree sql.NullInt64
+		var tableType string
+		var createTime, updateTime sql.NullTime
+		err := rows.Scan(&table.Name, &tableType, &table.Comment, &table.Engine, &table.Collation,
+			&table.RowFormat, &autoIncrement, &createTime, &updateTime,
+			&tableRows, &dataLength, &indexLength, &dataFree)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if autoIncrement.Valid {
+			table.AutoIncrement = &autoIncrement.Int64
+		}
+		if createTime.Valid {
+			table.CreateTime = &createTime.Time
+		}
+		if updateTime.Valid {
+			table.UpdateTime = &updateTime.Time
+		}
+		// InnoDB の TABLE_ROWS は統計情報による概算値
+		table.TableRows = nullInt64(tableRows)
+		table.DataLength = nullInt64(dataLength)
+		table.IndexLength = nullInt64(indexLength)
+		table.DataFree = nullInt64(dataFree)
+		if !opts.filter.AcceptTable(table.Name) {
+			continue
+		}
+		if tableType == "SYSTEM VERSIONED" {
+			table.SystemVersioned = true
+			versionedTables = append(versionedTables, table.Name)
+		}
 
-		if col.IsForeign {
-			// 外部キー情報の取得
-			fkTable, fkColumn, err := getForeignKeyInfo(db, tableName, col.Name)
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// テーブル単位の情報はスキーマ単位の問い合わせで取得するため、互いに独立した問い合わせを workers 個のワーカーで並列に実行する
+	var (
+		columns       map[string][]*sql_model.Column
+		uniqueColumns map[string]map[string]bool
+		indexes       map[string][]*sql_model.Index
+		foreignKeys   map[string][]*sql_model.ForeignKey
+		checks        map[string][]*sql_model.Check
+		triggers      map[string][]*sql_model.Trigger
+		partitions    map[string]*sql_model.Partitioning
+		periods       map[string][]*sql_model.Period
+	)
+	queries := []func(ctx context.Context) error{
+		// カラム情報の取得
+		func(ctx context.Context) (err error) {
+			columns, err = getColumns(ctx, db, dbName, opts.mariaDB)
+			return err
+		},
+		// ユニーク制約の取得
+		func(ctx context.Context) (err error) {
+			uniqueColumns, err = getUniqueColumns(ctx, db, dbName)
+			return err
+		},
+		// インデックス情報の取得
+		func(ctx context.Context) error {
+			indexQuery, err := buildIndexQuery(ctx, db)
 			if err != nil {
-				// エラー処理
-				return nil, err
+				return err
 			}
-			col.ForeignKeyTable = fkTable
-			col.ForeignKeyColumn = fkColumn
+			indexes, err = getIndexes(ctx, db, indexQuery, dbName)
+			return err
+		},
+		// 外部キー制約の取得
+		func(ctx context.Context) (err error) {
+			foreignKeys, err = getForeignKeys(ctx, db, dbName)
+			return err
+		},
+		// CHECK制約の取得
+		func(ctx context.Context) (err error) {
+			checks, err = getChecks(ctx, db, dbName)
+			return err
+		},
+		// トリガーの取得
+		func(ctx context.Context) (err error) {
+			triggers, err = getTriggers(ctx, db, dbName)
+			return err
+		},
+		// パーティション構成の取得
+		func(ctx context.Context) (err error) {
+			partitions, err = getPartitions(ctx, db, dbName)
+			return err
+		},
+	}
+	if len(versionedTables) > 0 {
+		// システムバージョニングの期間の取得（MariaDB のみ）
+		queries = append(queries, func(ctx context.Context) (err error) {
+			periods, err = getPeriods(ctx, db, dbName, versionedTables)
+			return err
+		})
+	}
+	err = inspector.ForEach(ctx, opts.workers, len(queries), func(ctx context.Context, i int) error {
+		return queries[i](ctx)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, table := range tables {
+		table.Columns = columns[table.Name]
+		table.Indexes = indexes[table.Name]
+		table.ForeignKeys = foreignKeys[table.Name]
+		table.Checks = checks[table.Name]
+		table.Triggers = triggers[table.Name]
+		table.Partitioning = partitions[table.Name]
+		table.Periods = periods[table.Name]
+		if opts.mariaDB {
+			applyJSONAliases(table)
 		}
 
-		columns = append(columns, col)
+		for _, col := range table.Columns {
+			col.IsUnique = uniqueColumns[table.Name][col.Name]
+		}
+		for _, idx := range table.Indexes {
+			for _, part := range idx.Columns {
+				if col := findColumn(table.Columns, part.Name); col != nil {
+					col.IsIndexed = true
+				}
+			}
+		}
+		applyUniquePrefixLengths(table)
+		applyForeignKeyInfo(table.ForeignKeys, table.Columns)
+	}
+
+	// ビューの取得（ビューのカラムも information_schema.COLUMNS に含まれる）
+	views, err := getViews(ctx, db, dbName)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, view := range views {
+		view.Columns = columns[view.Name]
 	}
 
-	return columns, nil
+	return tables, views, nil
 }
 
-func checkColumnIsUnique(db *sql.DB, tableName, columnName string) (bool, error) {
-	var count int
+// applyUniquePrefixLengths はプレフィックスのみに対するユニークインデックスのプレフィックス長をカラムに設定します。
+// カラム全体に対するユニークインデックスもある場合は設定しません。
+func applyUniquePrefixLengths(table *sql_model.Table) {
+	prefixLengths := make(map[string]int64)
+	for _, idx := range table.Indexes {
+		if !idx.IsUnique || idx.IsPrimary {
+			continue
+		}
+		for _, part := range idx.Columns {
+			length, ok := prefixLengths[part.Name]
+			if !ok || (length > 0 && (part.Length == 0 || part.Length < length)) {
+				prefixLengths[part.Name] = part.Length
+			}
+		}
+	}
+	for _, col := range table.Columns {
+		if col.IsUnique {
+			col.UniquePrefixLength = prefixLengths[col.Name]
+		}
+	}
+}
+
+// nullInt64 は sql.NullInt64 を NULL の場合はnilとなるポインタに変換します。
+func nullInt64(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+// getViews はスキーマ内のビューの定義を取得します。
+func getViews(ctx context.Context, db inspector.Querier, dbName string) ([]*sql_model.View, error) {
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var views []*sql_model.View
+
 	query := `
-    SELECT COUNT(*)
-    FROM information_schema.TABLE_CONSTRAINTS AS tc
-    JOIN information_schema.KEY_COLUMN_USAGE AS kcu ON tc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
-    WHERE tc.TABLE_SCHEMA = DATABASE() AND tc.TABLE_NAME = ? AND kcu.COLUMN_NAME = ? AND tc.CONSTRAINT_TYPE = 'UNIQUE'
+    SELECT TABLE_NAME, VIEW_DEFINITION, SECURITY_TYPE, CHECK_OPTION, IS_UPDATABLE
+    FROM information_schema.VIEWS
+    WHERE TABLE_SCHEMA = ?
+    ORDER BY TABLE_NAME
     `
-	err := db.QueryRow(query, tableName, columnName).Scan(&count)
+	rows, err := db.QueryContext(qctx, query, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		view := new(sql_model.View)
+		var isUpdatable string
+		if err := rows.Scan(&view.Name, &view.Definition, &view.SecurityType, &view.CheckOption, &isUpdatable); err != nil {
+			return nil, err
+		}
+		view.IsUpdatable = isUpdatable == "YES"
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 参照テーブルの取得
+	referencedTables, err := getViewTableUsage(ctx, db, dbName)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	for _, view := range views {
+		view.ReferencedTables = referencedTables[view.Name]
 	}
-	return count > 0, nil
+
+	return views, nil
 }
 
-func checkColumnIsIndexed(db *sql.DB, tableName, columnName string) (bool, error) {
+// getViewTableUsage はビューが参照しているテーブルをビュー名ごとに取得します。
+// VIEW_TABLE_USAGE は MySQL 8.0.13 で追加されたため、存在しない場合は参照テーブルを取得しません。
+func getViewTableUsage(ctx context.Context, db inspector.Querier, dbName string) (map[string][]string, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
 	var count int
-	query := `
+	err := db.QueryRowContext(ctx, `
     SELECT COUNT(*)
-    FROM information_schema.STATISTICS
-    WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?
+    FROM information_schema.TABLES
+    WHERE TABLE_SCHEMA = 'information_schema' AND TABLE_NAME = 'VIEW_TABLE_USAGE'
+    `).Scan(&count)
+	if err != nil || count == 0 {
+		return nil, err
+	}
+
+	query := `
+    SELECT VIEW_NAME, TABLE_SCHEMA, TABLE_NAME
+    FROM information_schema.VIEW_TABLE_USAGE
+    WHERE VIEW_SCHEMA = ?
+    ORDER BY VIEW_NAME, TABLE_SCHEMA, TABLE_NAME
     `
-	err := db.QueryRow(query, tableName, columnName).Scan(&count)
+	rows, err := db.QueryContext(ctx, query, dbName)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	referencedTables := make(map[string][]string)
+	for rows.Next() {
+		var viewName, tableSchema, tableName string
+		if err := rows.Scan(&viewName, &tableSchema, &tableName); err != nil {
+			return nil, err
+		}
+		// 別スキーマのテーブルを参照している場合はスキーマ名を付ける
+		if tableSchema != dbName {
+			tableName = tableSchema + "." + tableName
+		}
+		referencedTables[viewName] = append(referencedTables[viewName], tableName)
 	}
-	return count > 0, nil
+
+	return referencedTables, rows.Err()
 }
 
-func checkColumnIsForeignKey(db *sql.DB, tableName, columnName string) (bool, error) {
-	var count int
+// getColumns はスキーマ内の全テーブルのカラム情報をテーブル名ごとに取得します。
+// GENERATION_EXPRESSION は MySQL 5.7 から COLUMNS に追加されたため、存在しない場合は空文字列で補います。
+func getColumns(ctx context.Context, db inspector.Querier, dbName string, mariaDB bool) (map[string][]*sql_model.Column, error) {
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	columns := make(map[string][]*sql_model.Column)
+
+	available, err := availableColumns(ctx, db, "COLUMNS")
+	if err != nil {
+		return nil, err
+	}
+	generationExpression := "''"
+	if available["GENERATION_EXPRESSION"] {
+		generationExpression = "COALESCE(GENERATION_EXPRESSION, '')"
+	}
+
+	// カラム情報の取得
+	query := fmt.Sprintf(`
+    SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, COLUMN_COMMENT,
+           COLUMN_KEY, EXTRA, %s, COALESCE(CHARACTER_SET_NAME, ''), COALESCE(COLLATION_NAME, '')
+    FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = ?
+    ORDER BY TABLE_NAME, ORDINAL_POSITION
+    `, generationExpression)
+	rows, err := db.QueryContext(qctx, query, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		col := new(sql_model.Column)
+		var tableName, isNullable, columnKey string
+		var defaultVal sql.NullString
+		err := rows.Scan(&tableName, &col.Name, &col.Type, &isNullable, &defaultVal, &col.Comment, &columnKey,
+			&col.Extra, &col.Expression, &col.CharacterSet, &col.Collation)
+		if err != nil {
+			return nil, err
+		}
+
+		// デフォルト値がない場合・DEFAULT NULL の場合は COLUMN_DEFAULT が NULL となる
+		if defaultVal.Valid && mariaDB {
+			col.Default, col.IsDefaultExpression = mariaDBDefault(defaultVal.String)
+		} else if defaultVal.Valid {
+			col.Default = &defaultVal.String
+			col.IsDefaultExpression = isDefaultExpression(col.Type, col.Extra, defaultVal.String)
+		}
+		col.IsNullable = isNullable == "YES"
+		col.IsPrimaryKey = columnKey == "PRI"
+		col.Generated = generatedType(col.Extra)
+		col.EnumValues = enumValues(col.Type)
+
+		columns[tableName] = append(columns[tableName], col)
+	}
+
+	return columns, rows.Err()
+}
+
+// isDefaultExpression はデフォルト値が式かどうかを判定します。
+// MySQL 8.0.13 以降は式デフォルト（CURRENT_TIMESTAMP を含む）の EXTRA に DEFAULT_GENERATED が付きます。
+// それより前のバージョンで式を指定できるのは日時型の CURRENT_TIMESTAMP のみです。
+func isDefaultExpression(columnType, extra, defaultValue string) bool {
+	if strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED") {
+		return true
+	}
+	columnType = strings.ToLower(columnType)
+	isTemporal := strings.HasPrefix(columnType, "timestamp") || strings.HasPrefix(columnType, "datetime")
+	return isTemporal && strings.HasPrefix(strings.ToUpper(defaultValue), "CURRENT_TIMESTAMP")
+}
+
+// generatedType は EXTRA の値から生成列の種類（VIRTUAL / STORED）を求めます。
+// MariaDB は STORED の代わりに PERSISTENT と表示します。
+func generatedType(extra string) string {
+	extra = strings.ToUpper(extra)
+	switch {
+	case strings.Contains(extra, "VIRTUAL GENERATED"):
+		return "VIRTUAL"
+	case strings.Contains(extra, "STORED GENERATED"), strings.Contains(extra, "PERSISTENT GENERATED"):
+		return "STORED"
+	default:
+		return ""
+	}
+}
+
+// enumValues は COLUMN_TYPE が enum('a','b') / set('a','b') の場合に値の一覧を返します。
+// 値の中のシングルクォートは2つ重ねてエスケープされています。
+func enumValues(columnType string) []string {
+	lower := strings.ToLower(columnType)
+	if !strings.HasPrefix(lower, "enum(") && !strings.HasPrefix(lower, "set(") {
+		return nil
+	}
+
+	var values []string
+	var value strings.Builder
+	inQuote := false
+	body := columnType[strings.Index(columnType, "(")+1:]
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case inQuote && c == '\'' && i+1 < len(body) && body[i+1] == '\'':
+			i++
+			value.WriteByte(c)
+		case c == '\'':
+			if inQuote {
+				values = append(values, value.String())
+				value.Reset()
+			}
+			inQuote = !inQuote
+		case inQuote:
+			value.WriteByte(c)
+		}
+	}
+	return values
+}
+
+// getChecks はスキーマ内のCHECK制約をテーブル名ごとに取得します。
+// CHECK_CONSTRAINTS は MySQL 8.0.16 から追加されたため、存在しない場合は空のマップを返します。
+// MariaDB の CHECK制約名はテーブルごとに一意のため、TABLE_NAME を持つ場合は結合条件に含めます。
+func getChecks(ctx context.Context, db inspector.Querier, dbName string) (map[string][]*sql_model.Check, error) {
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	checks := make(map[string][]*sql_model.Check)
+
+	checkColumns, err := availableColumns(ctx, db, "CHECK_CONSTRAINTS")
+	if err != nil || len(checkColumns) == 0 {
+		return checks, err
+	}
+	constraintColumns, err := availableColumns(ctx, db, "TABLE_CONSTRAINTS")
+	if err != nil {
+		return nil, err
+	}
+	tableCondition, enforced := "", "'YES'"
+	if checkColumns["TABLE_NAME"] {
+		tableCondition = "AND tc.TABLE_NAME = cc.TABLE_NAME"
+	}
+	if constraintColumns["ENFORCED"] {
+		enforced = "tc.ENFORCED"
+	}
+
+	query := fmt.Sprintf(`
+    SELECT tc.TABLE_NAME, cc.CONSTRAINT_NAME, cc.CHECK_CLAUSE, %s
+    FROM information_schema.CHECK_CONSTRAINTS AS cc
+    JOIN information_schema.TABLE_CONSTRAINTS AS tc
+      ON tc.CONSTRAINT_SCHEMA = cc.CONSTRAINT_SCHEMA AND tc.CONSTRAINT_NAME = cc.CONSTRAINT_NAME %s
+     AND tc.CONSTRAINT_TYPE = 'CHECK'
+    WHERE cc.CONSTRAINT_SCHEMA = ?
+    ORDER BY tc.TABLE_NAME, cc.CONSTRAINT_NAME
+    `, enforced, tableCondition)
+	rows, err := db.QueryContext(qctx, query, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, isEnforced string
+		check := new(sql_model.Check)
+		if err := rows.Scan(&tableName, &check.Name, &check.Expression, &isEnforced); err != nil {
+			return nil, err
+		}
+		check.IsEnforced = isEnforced == "YES"
+		checks[tableName] = append(checks[tableName], check)
+	}
+
+	return checks, rows.Err()
+}
+
+// getUniqueColumns はスキーマ内のユニーク制約に含まれるカラムをテーブル名・カラム名ごとに取得します。
+func getUniqueColumns(ctx context.Context, db inspector.Querier, dbName string) (map[string]map[string]bool, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	uniqueColumns := make(map[string]map[string]bool)
+
 	query := `
-        SELECT COUNT(*)
-        FROM information_schema.KEY_COLUMN_USAGE
-        WHERE TABLE_SCHEMA = DATABASE()
-        AND TABLE_NAME = ?
-        AND COLUMN_NAME = ?
-        AND REFERENCED_TABLE_NAME IS NOT NULL
+    SELECT kcu.TABLE_NAME, kcu.COLUMN_NAME
+    FROM information_schema.TABLE_CONSTRAINTS AS tc
+    JOIN information_schema.KEY_COLUMN_USAGE AS kcu
+      ON kcu.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND kcu.TABLE_NAME = tc.TABLE_NAME
+     AND kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+    WHERE tc.TABLE_SCHEMA = ? AND tc.CONSTRAINT_TYPE = 'UNIQUE'
     `
-	err := db.QueryRow(query, tableName, columnName).Scan(&count)
+	rows, err := db.QueryContext(ctx, query, dbName)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, columnName string
+		if err := rows.Scan(&tableName, &columnName); err != nil {
+			return nil, err
+		}
+		if uniqueColumns[tableName] == nil {
+			uniqueColumns[tableName] = make(map[string]bool)
+		}
+		uniqueColumns[tableName][columnName] = true
 	}
-	return count > 0, nil
+
+	return uniqueColumns, rows.Err()
 }
 
-func getForeignKeyInfo(db *sql.DB, tableName, columnName string) (string, string, error) {
-	var fkTable, fkColumn string
+// getForeignKeys はスキーマ内の外部キー制約をテーブル名ごとに取得します。
+func getForeignKeys(ctx context.Context, db inspector.Querier, dbName string) (map[string][]*sql_model.ForeignKey, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	foreignKeys := make(map[string][]*sql_model.ForeignKey)
+
+	// 参照アクションは REFERENTIAL_CONSTRAINTS、カラムの対応は KEY_COLUMN_USAGE から取得する
 	query := `
-        SELECT REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
-        FROM information_schema.KEY_COLUMN_USAGE
-        WHERE TABLE_SCHEMA = DATABASE()
-        AND TABLE_NAME = ?
-        AND COLUMN_NAME = ?
-        AND REFERENCED_TABLE_NAME IS NOT NULL
+    SELECT kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.COLUMN_NAME, kcu.REFERENCED_TABLE_SCHEMA,
+           kcu.REFERENCED_TABLE_NAME, kcu.REFERENCED_COLUMN_NAME, rc.UPDATE_RULE, rc.DELETE_RULE
+    FROM information_schema.KEY_COLUMN_USAGE AS kcu
+    JOIN information_schema.REFERENTIAL_CONSTRAINTS AS rc
+      ON rc.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA AND rc.TABLE_NAME = kcu.TABLE_NAME
+     AND rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
+    WHERE kcu.TABLE_SCHEMA = ? AND kcu.REFERENCED_TABLE_NAME IS NOT NULL
+    ORDER BY kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION
     `
-	err := db.QueryRow(query, tableName, columnName).Scan(&fkTable, &fkColumn)
+	rows, err := db.QueryContext(ctx, query, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, name, columnName, refSchema, refTable, refColumn, updateRule, deleteRule string
+		err := rows.Scan(&tableName, &name, &columnName, &refSchema, &refTable, &refColumn, &updateRule, &deleteRule)
+		if err != nil {
+			return nil, err
+		}
+
+		// KEY_COLUMN_USAGE はカラム単位の行のため、同じ制約の行をまとめる
+		tableForeignKeys := foreignKeys[tableName]
+		var fk *sql_model.ForeignKey
+		if n := len(tableForeignKeys); n > 0 && tableForeignKeys[n-1].Name == name {
+			fk = tableForeignKeys[n-1]
+		} else {
+			// 別スキーマのテーブルを参照している場合はスキーマ名を付ける
+			if refSchema != dbName {
+				refTable = refSchema + "." + refTable
+			}
+			fk = &sql_model.ForeignKey{Name: name, ReferencedTable: refTable, UpdateRule: updateRule, DeleteRule: deleteRule}
+			foreignKeys[tableName] = append(tableForeignKeys, fk)
+		}
+		fk.Columns = append(fk.Columns, columnName)
+		fk.ReferencedColumns = append(fk.ReferencedColumns, refColumn)
+	}
+
+	return foreignKeys, rows.Err()
+}
+
+// applyForeignKeyInfo は外部キー制約からカラムの参照先情報を設定します。
+// 複数の外部キーに含まれるカラムは、最初の制約の参照先を表示します。
+func applyForeignKeyInfo(foreignKeys []*sql_model.ForeignKey, columns []*sql_model.Column) {
+	for _, fk := range foreignKeys {
+		for i, name := range fk.Columns {
+			col := findColumn(columns, name)
+			if col == nil || col.IsForeign {
+				continue
+			}
+			col.IsForeign = true
+			col.ForeignKeyTable = fk.ReferencedTable
+			col.ForeignKeyColumn = fk.ReferencedColumns[i]
+		}
+	}
+}
+
+// buildIndexQuery はサーバーのバージョンに合わせてインデックス情報を取得するクエリを組み立てます。
+// IS_VISIBLE は MySQL 8.0、EXPRESSION は MySQL 8.0.13 から STATISTICS に追加されたため、存在しない場合は既定値で補います。
+func buildIndexQuery(ctx context.Context, db inspector.Querier) (string, error) {
+	available, err := availableColumns(ctx, db, "STATISTICS")
 	if err != nil {
-		return "", "", err
+		return "", err
+	}
+
+	visible, expression := "'YES'", "NULL"
+	if available["IS_VISIBLE"] {
+		visible = "IS_VISIBLE"
+	}
+	if available["EXPRESSION"] {
+		expression = "EXPRESSION"
+	}
+
+	return fmt.Sprintf(`
+    SELECT TABLE_NAME, INDEX_NAME, NON_UNIQUE, COLUMN_NAME, %s, SUB_PART, COLLATION, INDEX_TYPE, %s,
+           COALESCE(INDEX_COMMENT, '')
+    FROM information_schema.STATISTICS
+    WHERE TABLE_SCHEMA = ?
+    ORDER BY TABLE_NAME, INDEX_NAME <> 'PRIMARY', INDEX_NAME, SEQ_IN_INDEX
+    `, expression, visible), nil
+}
+
+// availableColumns は information_schema のテーブルに存在するカラム名を返します。
+// サーバーのバージョンによって存在しないカラムを判定するために使います。テーブル自体がない場合は空のマップを返します。
+func availableColumns(ctx context.Context, db inspector.Querier, tableName string) (map[string]bool, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, `
+    SELECT COLUMN_NAME
+    FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = 'information_schema' AND TABLE_NAME = ?
+    `, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	available := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		available[strings.ToUpper(name)] = true
+	}
+	return available, rows.Err()
+}
+
+// getIndexes はスキーマ内のインデックス情報をテーブル名ごとに取得します。
+func getIndexes(ctx context.Context, db inspector.Querier, query, dbName string) (map[string][]*sql_model.Index, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	indexes := make(map[string][]*sql_model.Index)
+
+	rows, err := db.QueryContext(ctx, query, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, name, indexType, visible, comment string
+		var nonUnique bool
+		var columnName, expression, collation sql.NullString
+		var subPart sql.NullInt64
+		err := rows.Scan(&tableName, &name, &nonUnique, &columnName, &expression, &subPart, &collation, &indexType, &visible, &comment)
+		if err != nil {
+			return nil, err
+		}
+
+		// STATISTICS はカラム単位の行のため、同じインデックスの行をまとめる
+		tableIndexes := indexes[tableName]
+		var idx *sql_model.Index
+		if n := len(tableIndexes); n > 0 && tableIndexes[n-1].Name == name {
+			idx = tableIndexes[n-1]
+		} else {
+			idx = &sql_model.Index{
+				Name:      name,
+				IsPrimary: name == "PRIMARY",
+				IsUnique:  !nonUnique,
+				Type:      indexType,
+				IsVisible: visible == "YES",
+				Comment:   comment,
+			}
+			indexes[tableName] = append(tableIndexes, idx)
+		}
+
+		idx.Columns = append(idx.Columns, &sql_model.IndexColumn{
+			Name:       columnName.String,
+			Expression: expression.String,
+			Length:     subPart.Int64,
+			Descending: collation.String == "D",
+		})
+	}
+
+	return indexes, rows.Err()
+}
+
+func findColumn(columns []*sql_model.Column, name string) *sql_model.Column {
+	for _, col := range columns {
+		if strings.EqualFold(col.Name, name) {
+			return col
+		}
 	}
-	return fkTable, fkColumn, nil
+	return nil
 }
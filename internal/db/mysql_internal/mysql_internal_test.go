@@ -0,0 +1,151 @@
+package mysql_internal
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingDriver は発行されたクエリの数を数えるテスト用のドライバです。
+// テーブル一覧とカラム一覧のクエリには tables 個のテーブル・テーブルごとに columns 個のカラムを返し、
+// COUNT(*) のクエリには0を、それ以外のクエリには空の結果を返します。
+type countingDriver struct {
+	tables  int
+	columns int
+	queries int64
+}
+
+var driverSeq int64
+
+// openCountingDB は countingDriver を登録し、そのドライバで接続します。
+func openCountingDB(t testing.TB, tables, columns int) (*sql.DB, *countingDriver) {
+	t.Helper()
+	d := &countingDriver{tables: tables, columns: columns}
+	name := fmt.Sprintf("mysql_counting_%d", atomic.AddInt64(&driverSeq, 1))
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+func (d *countingDriver) Open(string) (driver.Conn, error) {
+	return &countingConn{driver: d}, nil
+}
+
+type countingConn struct {
+	driver *countingDriver
+}
+
+func (c *countingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *countingConn) Close() error { return nil }
+
+func (c *countingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c *countingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	atomic.AddInt64(&c.driver.queries, 1)
+
+	d := c.driver
+	switch {
+	case strings.Contains(query, "FROM information_schema.TABLES") && strings.Contains(query, "TABLE_TYPE NOT IN"):
+		rows := &fakeRows{columns: make([]string, 13)}
+		for i := 0; i < d.tables; i++ {
+			rows.values = append(rows.values, []driver.Value{
+				fmt.Sprintf("t%04d", i), "BASE TABLE", "", "InnoDB", "utf8mb4_general_ci",
+				"Dynamic", nil, nil, nil, int64(0), int64(16384), int64(0), int64(0),
+			})
+		}
+		return rows, nil
+	case strings.Contains(query, "FROM information_schema.COLUMNS") && strings.Contains(query, "ORDINAL_POSITION"):
+		rows := &fakeRows{columns: make([]string, 11)}
+		for i := 0; i < d.tables; i++ {
+			for j := 0; j < d.columns; j++ {
+				rows.values = append(rows.values, []driver.Value{
+					fmt.Sprintf("t%04d", i), fmt.Sprintf("c%d", j), "int", "YES", nil, "",
+					"", "", "", "", "",
+				})
+			}
+		}
+		return rows, nil
+	case strings.Contains(query, "COUNT(*)"):
+		return &fakeRows{columns: []string{"COUNT(*)"}, values: [][]driver.Value{{int64(0)}}}, nil
+	}
+	return &fakeRows{columns: []string{"COLUMN_NAME"}}, nil
+}
+
+type fakeRows struct {
+	mu      sync.Mutex
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func inspectWithCount(t testing.TB, tables, columns int) int64 {
+	t.Helper()
+	db, d := openCountingDB(t, tables, columns)
+	dbInfo, err := inspectSchema(context.Background(), db, "app", schemaOptions{workers: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dbInfo.Tables) != tables {
+		t.Fatalf("got %d tables, want %d", len(dbInfo.Tables), tables)
+	}
+	for _, table := range dbInfo.Tables {
+		if len(table.Columns) != columns {
+			t.Fatalf("table %s: got %d columns, want %d", table.Name, len(table.Columns), columns)
+		}
+	}
+	return atomic.LoadInt64(&d.queries)
+}
+
+// テーブル数・カラム数が増えてもクエリの数が変わらないこと
+func TestInspectSchemaQueryCount(t *testing.T) {
+	small := inspectWithCount(t, 10, 5)
+	large := inspectWithCount(t, 1000, 50)
+	if small != large {
+		t.Errorf("query count depends on schema size: %d queries for 10 tables, %d queries for 1000 tables", small, large)
+	}
+}
+
+func BenchmarkInspectSchema(b *testing.B) {
+	for _, size := range []struct{ tables, columns int }{{10, 5}, {1000, 50}} {
+		b.Run(fmt.Sprintf("tables=%d/columns=%d", size.tables, size.columns), func(b *testing.B) {
+			db, d := openCountingDB(b, size.tables, size.columns)
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				if _, err := inspectSchema(context.Background(), db, "app", schemaOptions{workers: 4}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(atomic.LoadInt64(&d.queries))/float64(b.N), "queries/op")
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package mysql_internal
+
+import (
+	"context"
+	"database/sql"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"strings"
+)
+
+// getPartitions はスキーマ内のパーティション構成をテーブル名ごとに取得します。
+// PARTITIONS はサブパーティション単位の行のため、パーティション単位にまとめ、推定行数はサブパーティションの合計とします。
+func getPartitions(ctx context.Context, db inspector.Querier, dbName string) (map[string]*sql_model.Partitioning, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	partitions := make(map[string]*sql_model.Partitioning)
+
+	query := `
+    SELECT TABLE_NAME, PARTITION_NAME, SUBPARTITION_NAME, PARTITION_METHOD, COALESCE(PARTITION_EXPRESSION, ''),
+           COALESCE(SUBPARTITION_METHOD, ''), COALESCE(SUBPARTITION_EXPRESSION, ''), PARTITION_DESCRIPTION,
+           TABLE_ROWS, PARTITION_COMMENT
+    FROM information_schema.PARTITIONS
+    WHERE TABLE_SCHEMA = ? AND PARTITION_NAME IS NOT NULL
+    ORDER BY TABLE_NAME, PARTITION_ORDINAL_POSITION, SUBPARTITION_ORDINAL_POSITION
+    `
+	rows, err := db.QueryContext(ctx, query, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, partitionName, method, expression, subMethod, subExpression, comment string
+		var subpartitionName, description sql.NullString
+		var tableRows sql.NullInt64
+		err := rows.Scan(&tableName, &partitionName, &subpartitionName, &method, &expression,
+			&subMethod, &subExpression, &description, &tableRows, &comment)
+		if err != nil {
+			return nil, err
+		}
+
+		partitioning := partitions[tableName]
+		if partitioning == nil {
+			partitioning = &sql_model.Partitioning{
+				Method:                 method,
+				Expression:             expression,
+				SubpartitionMethod:     subMethod,
+				SubpartitionExpression: subExpression,
+			}
+			partitions[tableName] = partitioning
+		}
+
+		var partition *sql_model.Partition
+		if n := len(partitioning.Partitions); n > 0 && partitioning.Partitions[n-1].Name == partitionName {
+			partition = partitioning.Partitions[n-1]
+		} else {
+			partition = &sql_model.Partition{
+				Name:    partitionName,
+				Bound:   partitionBound(method, description.String),
+				Comment: comment,
+			}
+			partitioning.Partitions = append(partitioning.Partitions, partition)
+		}
+
+		var estimate *int64
+		if tableRows.Valid {
+			estimate = &tableRows.Int64
+		}
+		if !subpartitionName.Valid {
+			partition.Rows = estimate
+			continue
+		}
+		partition.Subpartitions = append(partition.Subpartitions, &sql_model.Partition{Name: subpartitionName.String, Rows: estimate})
+		if estimate != nil {
+			total := *estimate
+			if partition.Rows != nil {
+				total += *partition.Rows
+			}
+			partition.Rows = &total
+		}
+	}
+
+	return partitions, rows.Err()
+}
+
+// partitionBound は PARTITION_DESCRIPTION の値を VALUES 句に近い表記へ変換します。
+func partitionBound(method, description string) string {
+	switch {
+	case description == "":
+		return ""
+	case strings.HasPrefix(method, "RANGE") && description == "MAXVALUE":
+		return "LESS THAN MAXVALUE"
+	case strings.HasPrefix(method, "RANGE"):
+		return "LESS THAN (" + description + ")"
+	case strings.HasPrefix(method, "LIST"):
+		return "IN (" + description + ")"
+	default:
+		return description
+	}
+}
@@ -0,0 +1,45 @@
+package mysql_internal
+
+import (
+	"context"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"strings"
+)
+
+// Profile はテーブルのデータをサンプリングし、カラムの値の傾向を調べます。
+// MySQL には TABLESAMPLE がないため、テーブルごとに LIMIT で指定した行数までを読み取ります。
+// テーブルは DB_WORKERS 個のワーカーで並列に調べます。
+func (i *Inspector) Profile(ctx context.Context, dbInfos []*sql_model.DB) error {
+	db, err := i.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	opts := i.cfg.ProfileOptions()
+	for _, dbInfo := range dbInfos {
+		dbName := dbInfo.Name
+		sampleQuery := func(table *sql_model.Table, columns []*sql_model.Column, limit int) string {
+			names := make([]string, len(columns))
+			for j, col := range columns {
+				names[j] = quoteIdentifier(col.Name)
+			}
+			return fmt.Sprintf("SELECT %s FROM %s.%s LIMIT %d",
+				strings.Join(names, ", "), quoteIdentifier(dbName), quoteIdentifier(table.Name), limit)
+		}
+
+		err := inspector.ForEach(ctx, i.cfg.Workers, len(dbInfo.Tables), func(ctx context.Context, j int) error {
+			table := dbInfo.Tables[j]
+			if err := inspector.ProfileTable(ctx, db, table, opts, sampleQuery); err != nil {
+				return fmt.Errorf("could not profile table %s.%s: %w", dbName, table.Name, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
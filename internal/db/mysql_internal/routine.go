@@ -0,0 +1,171 @@
+package mysql_internal
+
+import (
+	"context"
+	"database/sql"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+)
+
+// getRoutines はスキーマ内のストアドプロシージャ・ストアドファンクションとその引数を取得します。
+func getRoutines(ctx context.Context, db inspector.Querier, dbName string) ([]*sql_model.Routine, error) {
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var routines []*sql_model.Routine
+
+	// ROUTINE_DEFINITION は定義者以外で SHOW_ROUTINE 権限がない場合 NULL となる
+	query := `
+    SELECT ROUTINE_NAME, ROUTINE_TYPE, COALESCE(DTD_IDENTIFIER, ''), ROUTINE_BODY,
+           COALESCE(ROUTINE_DEFINITION, ''), SECURITY_TYPE, IS_DETERMINISTIC, SQL_DATA_ACCESS, DEFINER,
+           ROUTINE_COMMENT
+    FROM information_schema.ROUTINES
+    WHERE ROUTINE_SCHEMA = ?
+    ORDER BY ROUTINE_TYPE DESC, ROUTINE_NAME
+    `
+	rows, err := db.QueryContext(qctx, query, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		routine := &sql_model.Routine{}
+		var isDeterministic string
+		err := rows.Scan(&routine.Name, &routine.Type, &routine.ReturnType, &routine.Language,
+			&routine.Definition, &routine.SecurityType, &isDeterministic, &routine.DataAccess, &routine.Definer,
+			&routine.Comment)
+		if err != nil {
+			return nil, err
+		}
+		routine.IsDeterministic = isDeterministic == "YES"
+		routines = append(routines, routine)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 引数の取得
+	parameters, err := getParameters(ctx, db, dbName)
+	if err != nil {
+		return nil, err
+	}
+	for _, routine := range routines {
+		routine.Parameters = parameters[routine.Type+"."+routine.Name]
+	}
+
+	return routines, nil
+}
+
+// getParameters はスキーマ内のルーチンの引数を「種類.ルーチン名」ごとに取得します。
+// FUNCTION の戻り値（ORDINAL_POSITION = 0）は ROUTINES の DTD_IDENTIFIER で扱うため除きます。
+func getParameters(ctx context.Context, db inspector.Querier, dbName string) (map[string][]*sql_model.Parameter, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	parameters := make(map[string][]*sql_model.Parameter)
+
+	query := `
+    SELECT ROUTINE_TYPE, SPECIFIC_NAME, COALESCE(PARAMETER_MODE, 'IN'), COALESCE(PARAMETER_NAME, ''), DTD_IDENTIFIER
+    FROM information_schema.PARAMETERS
+    WHERE SPECIFIC_SCHEMA = ? AND ORDINAL_POSITION > 0
+    ORDER BY ROUTINE_TYPE, SPECIFIC_NAME, ORDINAL_POSITION
+    `
+	rows, err := db.QueryContext(ctx, query, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var routineType, routineName string
+		param := &sql_model.Parameter{}
+		if err := rows.Scan(&routineType, &routineName, &param.Mode, &param.Name, &param.DataType); err != nil {
+			return nil, err
+		}
+		key := routineType + "." + routineName
+		parameters[key] = append(parameters[key], param)
+	}
+
+	return parameters, rows.Err()
+}
+
+// getTriggers はスキーマ内のトリガーをテーブル名ごとに取得します。
+func getTriggers(ctx context.Context, db inspector.Querier, dbName string) (map[string][]*sql_model.Trigger, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	triggers := make(map[string][]*sql_model.Trigger)
+
+	query := `
+    SELECT EVENT_OBJECT_TABLE, TRIGGER_NAME, ACTION_TIMING, EVENT_MANIPULATION, ACTION_ORDER,
+           ACTION_STATEMENT, DEFINER
+    FROM information_schema.TRIGGERS
+    WHERE EVENT_OBJECT_SCHEMA = ?
+    ORDER BY EVENT_OBJECT_TABLE, FIELD(ACTION_TIMING, 'BEFORE', 'AFTER'),
+             FIELD(EVENT_MANIPULATION, 'INSERT', 'UPDATE', 'DELETE'), ACTION_ORDER
+    `
+	rows, err := db.QueryContext(ctx, query, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName string
+		trigger := &sql_model.Trigger{}
+		err := rows.Scan(&tableName, &trigger.Name, &trigger.Timing, &trigger.Event, &trigger.Order,
+			&trigger.Statement, &trigger.Definer)
+		if err != nil {
+			return nil, err
+		}
+		triggers[tableName] = append(triggers[tableName], trigger)
+	}
+
+	return triggers, rows.Err()
+}
+
+// getEvents はスキーマ内のイベントスケジューラのイベントを取得します。
+func getEvents(ctx context.Context, db inspector.Querier, dbName string) ([]*sql_model.Event, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var events []*sql_model.Event
+
+	query := `
+    SELECT EVENT_NAME, EVENT_DEFINITION, EVENT_TYPE, EXECUTE_AT, COALESCE(INTERVAL_VALUE, ''),
+           COALESCE(INTERVAL_FIELD, ''), STARTS, ENDS, STATUS, ON_COMPLETION, DEFINER, EVENT_COMMENT
+    FROM information_schema.EVENTS
+    WHERE EVENT_SCHEMA = ?
+    ORDER BY EVENT_NAME
+    `
+	rows, err := db.QueryContext(ctx, query, dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event := &sql_model.Event{}
+		var executeAt, starts, ends sql.NullTime
+		err := rows.Scan(&event.Name, &event.Definition, &event.Type, &executeAt, &event.IntervalValue,
+			&event.IntervalField, &starts, &ends, &event.Status, &event.OnCompletion, &event.Definer, &event.Comment)
+		if err != nil {
+			return nil, err
+		}
+
+		if executeAt.Valid {
+			event.ExecuteAt = &executeAt.Time
+		}
+		if starts.Valid {
+			event.Starts = &starts.Time
+		}
+		if ends.Valid {
+			event.Ends = &ends.Time
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
@@ -0,0 +1,180 @@
+package mysql_internal
+
+import (
+	"context"
+	"database/sql"
+	"export-db-info/internal/db/inspector"
+	"fmt"
+	"strings"
+)
+
+// begin はスナップショットから取得する場合に、専用の接続で読み取り専用トランザクションを開始します。
+// 問い合わせに使う接続と、取得後にトランザクションを終了して接続を返す関数を返します。
+func (i *Inspector) begin(ctx context.Context, db *sql.DB) (inspector.Querier, func(), error) {
+	if !i.cfg.Snapshot {
+		return db, func() {}, nil
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	// WITH CONSISTENT SNAPSHOT は REPEATABLE READ でのみ有効なため、次のトランザクションの分離レベルを指定する
+	for _, stmt := range []string{
+		"SET TRANSACTION ISOLATION LEVEL REPEATABLE READ",
+		"START TRANSACTION READ ONLY, WITH CONSISTENT SNAPSHOT",
+	} {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+	}
+
+	return conn, func() {
+		conn.ExecContext(context.Background(), "ROLLBACK")
+		conn.Close()
+	}, nil
+}
+
+// writePrivileges はデータ・スキーマの変更にあたる権限です。
+var writePrivileges = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "CREATE": true, "DROP": true, "ALTER": true, "INDEX": true,
+	"CREATE VIEW": true, "CREATE ROUTINE": true, "ALTER ROUTINE": true, "TRIGGER": true, "EVENT": true,
+}
+
+// checkReadOnlyUser は接続ユーザーが対象データベースへの書き込み権限を持つ場合にエラーを返します。
+// 権限は information_schema の USER_PRIVILEGES・SCHEMA_PRIVILEGES・TABLE_PRIVILEGES から、
+// 接続ユーザーとセッションで有効なロール（MySQL 8・MariaDB のロール）のそれぞれについて確認します。
+func (i *Inspector) checkReadOnlyUser(ctx context.Context, db inspector.Querier, schemas []string) error {
+	if i.cfg.AllowWrite {
+		return nil
+	}
+
+	var currentUser string
+	if err := db.QueryRowContext(ctx, "SELECT CURRENT_USER()").Scan(&currentUser); err != nil {
+		return err
+	}
+	grantees := []string{currentUser}
+	if at := strings.LastIndex(currentUser, "@"); at >= 0 {
+		grantees[0] = formatGrantee(currentUser[:at], currentUser[at+1:])
+	}
+	roles, err := getEnabledRoles(ctx, db)
+	if err != nil {
+		return err
+	}
+	grantees = append(grantees, roles...)
+
+	var granted []string
+	seen := make(map[string]bool)
+	for _, schema := range schemas {
+		privileges, err := getWritePrivileges(ctx, db, grantees, schema)
+		if err != nil {
+			return err
+		}
+		for _, privilege := range privileges {
+			if !seen[privilege] {
+				seen[privilege] = true
+				granted = append(granted, privilege)
+			}
+		}
+	}
+	if len(granted) > 0 {
+		return fmt.Errorf("user %s has write privileges (%s); set DB_ALLOW_WRITE_USER=true to run anyway",
+			currentUser, strings.Join(granted, ", "))
+	}
+	return nil
+}
+
+// formatGrantee は information_schema の GRANTEE の形式（'ユーザー名'@'ホスト名'）を返します。
+func formatGrantee(user, host string) string {
+	return fmt.Sprintf("'%s'@'%s'", user, host)
+}
+
+// getEnabledRoles はセッションで有効なロールの GRANTEE を information_schema.ENABLED_ROLES から取得します。
+// ENABLED_ROLES がないサーバー（MySQL 8.0.19 より前など）では空を返します。
+// MariaDB のロールはホスト名を持たないため、GRANTEE は 'ロール名' の形式となります。
+func getEnabledRoles(ctx context.Context, db inspector.Querier) ([]string, error) {
+	available, err := availableColumns(ctx, db, "ENABLED_ROLES")
+	if err != nil || !available["ROLE_NAME"] {
+		return nil, err
+	}
+	roleHost := "NULL"
+	if available["ROLE_HOST"] {
+		roleHost = "ROLE_HOST"
+	}
+
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`
+    SELECT ROLE_NAME, %s
+    FROM information_schema.ENABLED_ROLES
+    WHERE ROLE_NAME IS NOT NULL
+    `, roleHost))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []string
+	for rows.Next() {
+		var name string
+		var host sql.NullString
+		if err := rows.Scan(&name, &host); err != nil {
+			return nil, err
+		}
+		if host.Valid {
+			roles = append(roles, formatGrantee(name, host.String))
+		} else {
+			roles = append(roles, fmt.Sprintf("'%s'", name))
+		}
+	}
+	return roles, rows.Err()
+}
+
+// getWritePrivileges は grantees がデータベース schema に対して持つ書き込み権限を「権限 ON 対象」の形式で取得します。
+func getWritePrivileges(ctx context.Context, db inspector.Querier, grantees []string, schema string) ([]string, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	in := "?" + strings.Repeat(", ?", len(grantees)-1)
+	// データベース単位の権限はワイルドカード（LIKE パターン）で付与されている場合がある
+	query := fmt.Sprintf(`
+    SELECT DISTINCT PRIVILEGE_TYPE, '*.*'
+    FROM information_schema.USER_PRIVILEGES
+    WHERE GRANTEE IN (%[1]s)
+    UNION ALL
+    SELECT DISTINCT PRIVILEGE_TYPE, CONCAT(TABLE_SCHEMA, '.*')
+    FROM information_schema.SCHEMA_PRIVILEGES
+    WHERE GRANTEE IN (%[1]s) AND ? LIKE TABLE_SCHEMA
+    UNION ALL
+    SELECT DISTINCT PRIVILEGE_TYPE, CONCAT(TABLE_SCHEMA, '.', TABLE_NAME)
+    FROM information_schema.TABLE_PRIVILEGES
+    WHERE GRANTEE IN (%[1]s) AND TABLE_SCHEMA = ?
+    `, in)
+	granteeArgs := make([]interface{}, len(grantees))
+	for i, grantee := range grantees {
+		granteeArgs[i] = grantee
+	}
+	var args []interface{}
+	args = append(args, granteeArgs...)
+	args = append(append(args, granteeArgs...), schema)
+	args = append(append(args, granteeArgs...), schema)
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var privileges []string
+	for rows.Next() {
+		var privilege, object string
+		if err := rows.Scan(&privilege, &object); err != nil {
+			return nil, err
+		}
+		if writePrivileges[privilege] {
+			privileges = append(privileges, privilege+" ON "+object)
+		}
+	}
+	return privileges, rows.Err()
+}
@@ -0,0 +1,96 @@
+package postgres_internal
+
+import (
+	"context"
+	"database/sql"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"strings"
+)
+
+// getPartitioning はパーティションテーブルのパーティションキーとパーティションを取得します。
+// パーティション自体がパーティション化されている場合は、1段階目の構成をサブパーティションとして扱います。
+func getPartitioning(ctx context.Context, db inspector.Querier, tableOid int64) (*sql_model.Partitioning, error) {
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var keyDef string
+	err := db.QueryRowContext(qctx, `SELECT pg_catalog.pg_get_partkeydef($1)`, tableOid).Scan(&keyDef)
+	if err != nil {
+		return nil, err
+	}
+
+	// pg_get_partkeydef は「RANGE (created_at)」の形式で返す
+	method, expression, _ := strings.Cut(keyDef, " ")
+	partitioning := &sql_model.Partitioning{
+		Method:     strings.ToUpper(method),
+		Expression: strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(expression), "("), ")"),
+	}
+
+	query := `
+    SELECT c.oid, c.relname, COALESCE(pg_catalog.pg_get_expr(c.relpartbound, c.oid), ''),
+           CASE WHEN c.relkind = 'p' OR c.reltuples < 0 THEN NULL ELSE c.reltuples::bigint END,
+           COALESCE(pg_catalog.obj_description(c.oid, 'pg_class'), ''), c.relkind = 'p'
+    FROM pg_catalog.pg_inherits AS i
+    JOIN pg_catalog.pg_class AS c ON c.oid = i.inhrelid
+    WHERE i.inhparent = $1
+    ORDER BY pg_catalog.pg_get_expr(c.relpartbound, c.oid) = 'DEFAULT', c.relname
+    `
+	rows, err := db.QueryContext(qctx, query, tableOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subpartitioned []int64
+	var subpartitionedParts []*sql_model.Partition
+	for rows.Next() {
+		partition := new(sql_model.Partition)
+		var oid int64
+		var bound string
+		var estimate sql.NullInt64
+		var isPartitioned bool
+		if err := rows.Scan(&oid, &partition.Name, &bound, &estimate, &partition.Comment, &isPartitioned); err != nil {
+			return nil, err
+		}
+
+		// 「FOR VALUES FROM (...) TO (...)」から先頭の FOR VALUES を取り除く
+		partition.Bound = strings.TrimPrefix(bound, "FOR VALUES ")
+		if estimate.Valid {
+			partition.Rows = &estimate.Int64
+		}
+		if isPartitioned {
+			subpartitioned = append(subpartitioned, oid)
+			subpartitionedParts = append(subpartitionedParts, partition)
+		}
+		partitioning.Partitions = append(partitioning.Partitions, partition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, oid := range subpartitioned {
+		sub, err := getPartitioning(ctx, db, oid)
+		if err != nil {
+			return nil, err
+		}
+		if partitioning.SubpartitionMethod == "" {
+			partitioning.SubpartitionMethod = sub.Method
+			partitioning.SubpartitionExpression = sub.Expression
+		}
+
+		partition := subpartitionedParts[i]
+		for _, subpartition := range sub.Partitions {
+			partition.Subpartitions = append(partition.Subpartitions, subpartition)
+			if subpartition.Rows != nil {
+				total := *subpartition.Rows
+				if partition.Rows != nil {
+					total += *partition.Rows
+				}
+				partition.Rows = &total
+			}
+		}
+	}
+
+	return partitioning, nil
+}
@@ -0,0 +1,755 @@
+package postgres_internal
+
+import (
+	"context"
+	"database/sql"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"export-db-info/pkg/db/postgres"
+	"fmt"
+	"github.com/lib/pq"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	inspector.Register("postgres", NewInspector)
+}
+
+// Inspector はPostgreSQLのpg_catalogからスキーマ情報を取得します。
+type Inspector struct {
+	cfg    *inspector.Config
+	filter *inspector.Filter // テーブル単位の問い合わせの前に対象のテーブルを絞り込む
+}
+
+// NewInspector は接続設定からPostgreSQL用のSchemaInspectorを生成します。
+func NewInspector(cfg *inspector.Config) (inspector.SchemaInspector, error) {
+	filter, err := inspector.NewFilter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Inspector{cfg: cfg, filter: filter}, nil
+}
+
+// Inspect はデータベースに接続し、対象スキーマのテーブル・ビュー・カラム・シーケンス・ルーチンの情報を取得します。
+// 対象スキーマが未指定の場合は public のみを対象とします。
+func (i *Inspector) Inspect(ctx context.Context) (*sql_model.DB, error) {
+	db, err := i.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	schemas := i.cfg.Schemas
+	if len(schemas) == 0 {
+		schemas = []string{"public"}
+	}
+
+	if err := i.checkReadOnlyUser(ctx, db, schemas); err != nil {
+		return nil, err
+	}
+	pool, end, err := i.begin(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	return inspectSchemas(ctx, pool, i.filter, i.cfg.Database, schemas)
+}
+
+// InspectSchemas はデータベースに接続し、patterns に一致するスキーマごとに情報を取得します。
+// 各スキーマの情報はスキーマ名を名前とする sql_model.DB として返します。
+// スナップショットから取得する場合は、すべてのスキーマを同じトランザクション内で取得します。
+func (i *Inspector) InspectSchemas(ctx context.Context, patterns []string) ([]*sql_model.DB, error) {
+	db, err := i.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	schemas, err := listSchemas(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	schemas, err = inspector.MatchSchemas(schemas, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.checkReadOnlyUser(ctx, db, schemas); err != nil {
+		return nil, err
+	}
+	pool, end, err := i.begin(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	var dbs []*sql_model.DB
+	for _, schema := range schemas {
+		dbInfo, err := inspectSchemas(ctx, pool, i.filter, schema, []string{schema})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", schema, err)
+		}
+		dbs = append(dbs, dbInfo)
+	}
+	return dbs, nil
+}
+
+// connect はデータベースに接続し、疎通を確認します。
+func (i *Inspector) connect(ctx context.Context) (*sql.DB, error) {
+	// SSLモードが未指定の場合は、TLSの設定から検証の有無を決める
+	sslMode := i.cfg.SSLMode
+	if sslMode == "" && i.cfg.UseTLS() {
+		sslMode = "verify-full"
+		if i.cfg.TLSSkipVerify {
+			sslMode = "require"
+		}
+	}
+
+	db, err := postgres.Connect(postgres.Options{
+		Username:    i.cfg.Username,
+		Password:    i.cfg.Password,
+		Host:        i.cfg.Host,
+		Port:        i.cfg.Port,
+		Database:    i.cfg.Database,
+		Socket:      i.cfg.Socket,
+		SSLMode:     sslMode,
+		SSLRootCert: i.cfg.TLSCA,
+		SSLCert:     i.cfg.TLSCert,
+		SSLKey:      i.cfg.TLSKey,
+		Params:      i.cfg.Params,
+		DSN:         i.cfg.DSN,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// listSchemas はシステムスキーマ（pg_catalog・information_schema・pg_toast など pg_ で始まるもの）を除くスキーマの一覧を取得します。
+func listSchemas(ctx context.Context, db inspector.Querier) ([]string, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+    SELECT nspname
+    FROM pg_catalog.pg_namespace
+    WHERE nspname <> 'information_schema' AND nspname NOT LIKE 'pg\_%'
+    ORDER BY nspname
+    `
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, rows.Err()
+}
+
+// inspectSchemas は指定したスキーマのテーブル・ビュー・シーケンス・ルーチンの情報を name という名前の sql_model.DB にまとめます。
+// テーブルごとの情報は pool の接続で並列に取得し、filter で除外したテーブルは取得しません。
+func inspectSchemas(ctx context.Context, pool *inspector.QuerierPool, filter *inspector.Filter, name string, schemas []string) (*sql_model.DB, error) {
+	// 各テーブルとそのカラム情報を取得
+	tables, err := getTables(ctx, pool, filter, schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	db := pool.Primary()
+
+	// ビューとそのカラム情報を取得
+	views, err := getViews(ctx, db, filter, schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	// シーケンス情報を取得
+	sequences, err := getSequences(ctx, db, schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	// ファンクション・プロシージャを取得
+	routines, err := getRoutines(ctx, db, schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sql_model.DB{Name: name, Tables: tables, Views: views, Sequences: sequences, Routines: routines}, nil
+}
+
+// tableRef はテーブル一覧から取得したテーブルの識別情報と統計情報です。
+type tableRef struct {
+	oid                   int64
+	schema, name, comment string
+	isPartitioned         bool
+	rows                  sql.NullInt64 // 推定行数（ANALYZE されていない場合はNULL）
+	dataLength            int64
+	indexLength           int64
+}
+
+// getTables はテーブルの一覧を取得した後、filter で除外したものを除くテーブルごとの情報を pool の接続の数のワーカーで並列に取得します。
+// 結果はスキーマ名・テーブル名の順に返します。
+func getTables(ctx context.Context, pool *inspector.QuerierPool, filter *inspector.Filter, schemas []string) ([]*sql_model.Table, error) {
+	db := pool.Primary()
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	// テーブル一覧の取得（通常テーブルとパーティションの親テーブル）
+	query := `
+    SELECT c.oid, n.nspname, c.relname, COALESCE(pg_catalog.obj_description(c.oid, 'pg_class'), ''),
+           c.relkind = 'p', CASE WHEN c.reltuples < 0 THEN NULL ELSE c.reltuples::bigint END,
+           pg_catalog.pg_table_size(c.oid), pg_catalog.pg_indexes_size(c.oid)
+    FROM pg_catalog.pg_class AS c
+    JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+    WHERE c.relkind IN ('r', 'p') AND NOT c.relispartition AND n.nspname = ANY($1)
+    ORDER BY n.nspname, c.relname
+    `
+	rows, err := db.QueryContext(qctx, query, pq.Array(schemas))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []tableRef
+	for rows.Next() {
+		var ref tableRef
+		err := rows.Scan(&ref.oid, &ref.schema, &ref.name, &ref.comment, &ref.isPartitioned,
+			&ref.rows, &ref.dataLength, &ref.indexLength)
+		if err != nil {
+			return nil, err
+		}
+		if filter.AcceptTable(qualifiedName(ref.schema, ref.name, len(schemas) > 1)) {
+			refs = append(refs, ref)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	tables := make([]*sql_model.Table, len(refs))
+	err = inspector.ForEach(ctx, pool.Size(), len(refs), func(ctx context.Context, i int) error {
+		return pool.Do(ctx, func(q inspector.Querier) error {
+			table, err := getTable(ctx, q, refs[i], len(schemas) > 1)
+			if err != nil {
+				return err
+			}
+			tables[i] = table
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// getTable は1つのテーブルのカラム・インデックス・外部キー・CHECK制約・トリガー・パーティション構成を取得します。
+// qualify が true の場合はテーブル名をスキーマ名で修飾します。
+func getTable(ctx context.Context, db inspector.Querier, ref tableRef, qualify bool) (*sql_model.Table, error) {
+	// カラム情報の取得
+	columns, err := getColumns(ctx, db, ref.oid)
+	if err != nil {
+		return nil, err
+	}
+
+	// インデックス情報の取得
+	indexes, err := getIndexes(ctx, db, ref.oid)
+	if err != nil {
+		return nil, err
+	}
+	applyIndexInfo(indexes, columns)
+
+	// 外部キー制約の取得
+	foreignKeys, err := getForeignKeys(ctx, db, ref.oid, ref.schema, qualify)
+	if err != nil {
+		return nil, err
+	}
+	applyForeignKeyInfo(foreignKeys, columns)
+
+	// CHECK制約の取得
+	checks, err := getChecks(ctx, db, ref.oid)
+	if err != nil {
+		return nil, err
+	}
+
+	// トリガーの取得
+	triggers, err := getTriggers(ctx, db, ref.oid)
+	if err != nil {
+		return nil, err
+	}
+
+	// パーティション構成の取得
+	var partitioning *sql_model.Partitioning
+	if ref.isPartitioned {
+		if partitioning, err = getPartitioning(ctx, db, ref.oid); err != nil {
+			return nil, err
+		}
+	}
+
+	table := &sql_model.Table{
+		Schema:       ref.schema,
+		Name:         qualifiedName(ref.schema, ref.name, qualify),
+		Columns:      columns,
+		Indexes:      indexes,
+		ForeignKeys:  foreignKeys,
+		Checks:       checks,
+		Triggers:     triggers,
+		Partitioning: partitioning,
+		Comment:      ref.comment,
+		DataLength:   &ref.dataLength,
+		IndexLength:  &ref.indexLength,
+	}
+	if ref.rows.Valid {
+		table.TableRows = &ref.rows.Int64
+	}
+	return table, nil
+}
+
+func getColumns(ctx context.Context, db inspector.Querier, tableOid int64) ([]*sql_model.Column, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var columns []*sql_model.Column
+
+	// カラム情報の取得（照合順序はデータ型の既定と異なる場合のみ取得する）
+	query := `
+    SELECT a.attname, pg_catalog.format_type(a.atttypid, a.atttypmod), NOT a.attnotnull,
+           pg_catalog.pg_get_expr(d.adbin, d.adrelid), COALESCE(pg_catalog.col_description(a.attrelid, a.attnum), ''),
+           a.attidentity, a.attgenerated,
+           COALESCE((SELECT c.collname FROM pg_catalog.pg_collation AS c, pg_catalog.pg_type AS t
+                     WHERE c.oid = a.attcollation AND t.oid = a.atttypid AND a.attcollation <> t.typcollation), ''),
+           COALESCE((SELECT array_agg(e.enumlabel ORDER BY e.enumsortorder) FROM pg_catalog.pg_enum AS e
+                     WHERE e.enumtypid = a.atttypid), '{}')
+    FROM pg_catalog.pg_attribute AS a
+    LEFT JOIN pg_catalog.pg_attrdef AS d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+    WHERE a.attrelid = $1 AND a.attnum > 0 AND NOT a.attisdropped
+    ORDER BY a.attnum
+    `
+	rows, err := db.QueryContext(ctx, query, tableOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		col := new(sql_model.Column)
+		var defaultVal sql.NullString
+		var identity, generated string
+		err := rows.Scan(&col.Name, &col.Type, &col.IsNullable, &defaultVal, &col.Comment, &identity, &generated,
+			&col.Collation, pq.Array(&col.EnumValues))
+		if err != nil {
+			return nil, err
+		}
+
+		// 生成列の場合、pg_attrdef にはデフォルト値ではなく生成式が入る
+		switch generated {
+		case "s":
+			col.Generated = "STORED"
+		case "v":
+			col.Generated = "VIRTUAL"
+		}
+		if col.Generated != "" {
+			col.Expression = defaultVal.String
+			defaultVal = sql.NullString{}
+		}
+
+		if defaultVal.Valid {
+			col.Default, col.IsDefaultExpression = parseDefault(defaultVal.String)
+		}
+
+		switch identity {
+		case "a":
+			col.Identity = "ALWAYS"
+		case "d":
+			col.Identity = "BY DEFAULT"
+		}
+
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+// literalDefaultPattern は pg_get_expr が返すデフォルト値のうち、型キャスト付きの文字列リテラルに一致します。
+var literalDefaultPattern = regexp.MustCompile(`^'((?:[^']|'')*)'(?:::[\w\s."\[\]()]+)?$`)
+
+// numericDefaultPattern は数値リテラルに一致します（負の数は pg_get_expr で '-1'::integer となる）。
+var numericDefaultPattern = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
+// parseDefault は pg_get_expr が返すデフォルト値を、値と式かどうかに分けます。
+// 文字列・数値・真偽値のリテラルは引用符と型キャストを外した値とし、それ以外（nextval() や now() など）は式として扱います。
+// DEFAULT NULL は NULL::型 として保存されるため、デフォルト値なしとしてnilを返します。
+func parseDefault(expression string) (*string, bool) {
+	switch {
+	case strings.HasPrefix(expression, "NULL::") || expression == "NULL":
+		return nil, false
+	case literalDefaultPattern.MatchString(expression):
+		value := strings.ReplaceAll(literalDefaultPattern.FindStringSubmatch(expression)[1], "''", "'")
+		return &value, false
+	case numericDefaultPattern.MatchString(expression), expression == "true", expression == "false":
+		return &expression, false
+	default:
+		return &expression, true
+	}
+}
+
+// getChecks はテーブルのCHECK制約を取得します。NOT NULL 制約は含みません。
+func getChecks(ctx context.Context, db inspector.Querier, tableOid int64) ([]*sql_model.Check, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var checks []*sql_model.Check
+
+	query := `
+    SELECT con.conname, pg_catalog.pg_get_constraintdef(con.oid, true)
+    FROM pg_catalog.pg_constraint AS con
+    WHERE con.conrelid = $1 AND con.contype = 'c'
+    ORDER BY con.conname
+    `
+	rows, err := db.QueryContext(ctx, query, tableOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		check := &sql_model.Check{IsEnforced: true}
+		var definition string
+		if err := rows.Scan(&check.Name, &definition); err != nil {
+			return nil, err
+		}
+		// 「CHECK (式) [NO INHERIT] [NOT VALID]」から式の部分を取り出す
+		definition = strings.TrimSuffix(definition, " NOT VALID")
+		definition = strings.TrimSuffix(definition, " NO INHERIT")
+		check.Expression = strings.TrimPrefix(definition, "CHECK ")
+		checks = append(checks, check)
+	}
+
+	return checks, rows.Err()
+}
+
+func getIndexes(ctx context.Context, db inspector.Querier, tableOid int64) ([]*sql_model.Index, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var indexes []*sql_model.Index
+
+	// 関数インデックスの式は attnum が0となるため pg_get_indexdef で取得する
+	// INCLUDE 句のカラムはキーではないため対象外とする
+	query := `
+    SELECT ic.relname, i.indisprimary, i.indisunique, upper(am.amname), i.indisvalid,
+           COALESCE(pg_catalog.obj_description(i.indexrelid, 'pg_class'), ''),
+           COALESCE(pg_catalog.pg_get_expr(i.indpred, i.indrelid), ''),
+           COALESCE(a.attname, ''),
+           CASE WHEN k.attnum = 0 THEN pg_catalog.pg_get_indexdef(i.indexrelid, k.ord::int, true) ELSE '' END,
+           (i.indoption[(k.ord - 1)::int] & 1) = 1
+    FROM pg_catalog.pg_index AS i
+    JOIN pg_catalog.pg_class AS ic ON ic.oid = i.indexrelid
+    JOIN pg_catalog.pg_am AS am ON am.oid = ic.relam
+    CROSS JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+    LEFT JOIN pg_catalog.pg_attribute AS a ON a.attrelid = i.indrelid AND a.attnum = k.attnum AND k.attnum > 0
+    WHERE i.indrelid = $1 AND k.ord <= i.indnkeyatts
+    ORDER BY i.indisprimary DESC, ic.relname, k.ord
+    `
+	rows, err := db.QueryContext(ctx, query, tableOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		idx := new(sql_model.Index)
+		part := new(sql_model.IndexColumn)
+		err := rows.Scan(&idx.Name, &idx.IsPrimary, &idx.IsUnique, &idx.Type, &idx.IsVisible, &idx.Comment,
+			&idx.Condition, &part.Name, &part.Expression, &part.Descending)
+		if err != nil {
+			return nil, err
+		}
+
+		// 同じインデックスの行をまとめる
+		if n := len(indexes); n > 0 && indexes[n-1].Name == idx.Name {
+			idx = indexes[n-1]
+		} else {
+			indexes = append(indexes, idx)
+		}
+		idx.Columns = append(idx.Columns, part)
+	}
+
+	return indexes, rows.Err()
+}
+
+// applyIndexInfo はテーブルのインデックス定義からカラムの主キー・ユニーク・インデックス情報を設定します。
+// 部分インデックスによるユニーク制約はテーブル全体での一意性を保証しないため、インデックスとしてのみ扱います。
+func applyIndexInfo(indexes []*sql_model.Index, columns []*sql_model.Column) {
+	for _, idx := range indexes {
+		for _, part := range idx.Columns {
+			col := findColumn(columns, part.Name)
+			if col == nil {
+				continue
+			}
+			col.IsIndexed = true
+			if idx.IsPrimary {
+				col.IsPrimaryKey = true
+			} else if idx.IsUnique && idx.Condition == "" {
+				col.IsUnique = true
+			}
+		}
+	}
+}
+
+// getForeignKeys はテーブルの外部キー制約を取得します。
+// 参照先が別スキーマのテーブルの場合、または複数スキーマを対象とする場合は参照先をスキーマ名付きで表します。
+func getForeignKeys(ctx context.Context, db inspector.Querier, tableOid int64, schema string, qualify bool) ([]*sql_model.ForeignKey, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var foreignKeys []*sql_model.ForeignKey
+
+	query := `
+    SELECT con.conname, a.attname, fn.nspname, fc.relname, fa.attname, con.confupdtype, con.confdeltype
+    FROM pg_catalog.pg_constraint AS con
+    CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, fattnum, ord)
+    JOIN pg_catalog.pg_attribute AS a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+    JOIN pg_catalog.pg_class AS fc ON fc.oid = con.confrelid
+    JOIN pg_catalog.pg_namespace AS fn ON fn.oid = fc.relnamespace
+    JOIN pg_catalog.pg_attribute AS fa ON fa.attrelid = con.confrelid AND fa.attnum = k.fattnum
+    WHERE con.conrelid = $1 AND con.contype = 'f'
+    ORDER BY con.conname, k.ord
+    `
+	rows, err := db.QueryContext(ctx, query, tableOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, columnName, fkSchema, fkTable, fkColumn, updateType, deleteType string
+		err := rows.Scan(&name, &columnName, &fkSchema, &fkTable, &fkColumn, &updateType, &deleteType)
+		if err != nil {
+			return nil, err
+		}
+
+		// 同じ制約の行をまとめる
+		var fk *sql_model.ForeignKey
+		if n := len(foreignKeys); n > 0 && foreignKeys[n-1].Name == name {
+			fk = foreignKeys[n-1]
+		} else {
+			fk = &sql_model.ForeignKey{
+				Name:            name,
+				ReferencedTable: qualifiedName(fkSchema, fkTable, qualify || fkSchema != schema),
+				UpdateRule:      referentialAction(updateType),
+				DeleteRule:      referentialAction(deleteType),
+			}
+			foreignKeys = append(foreignKeys, fk)
+		}
+		fk.Columns = append(fk.Columns, columnName)
+		fk.ReferencedColumns = append(fk.ReferencedColumns, fkColumn)
+	}
+
+	return foreignKeys, rows.Err()
+}
+
+// referentialAction は pg_constraint の confupdtype / confdeltype を参照アクション名に変換します。
+func referentialAction(code string) string {
+	switch code {
+	case "c":
+		return "CASCADE"
+	case "n":
+		return "SET NULL"
+	case "d":
+		return "SET DEFAULT"
+	case "r":
+		return "RESTRICT"
+	default:
+		return "NO ACTION"
+	}
+}
+
+// applyForeignKeyInfo は外部キー制約からカラムの参照先情報を設定します。
+// 複数の外部キーに含まれるカラムは、最初の制約の参照先を表示します。
+func applyForeignKeyInfo(foreignKeys []*sql_model.ForeignKey, columns []*sql_model.Column) {
+	for _, fk := range foreignKeys {
+		for i, name := range fk.Columns {
+			col := findColumn(columns, name)
+			if col == nil || col.IsForeign {
+				continue
+			}
+			col.IsForeign = true
+			col.ForeignKeyTable = fk.ReferencedTable
+			col.ForeignKeyColumn = fk.ReferencedColumns[i]
+		}
+	}
+}
+
+func getViews(ctx context.Context, db inspector.Querier, filter *inspector.Filter, schemas []string) ([]*sql_model.View, error) {
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var views []*sql_model.View
+
+	// ビュー一覧の取得
+	// security_invoker（PostgreSQL 15以降）と check_option はビューのオプション（reloptions）に保存される
+	query := `
+    SELECT c.oid, n.nspname, c.relname, COALESCE(pg_catalog.pg_get_viewdef(c.oid, true), ''),
+           CASE WHEN EXISTS (
+               SELECT 1 FROM unnest(c.reloptions) AS o
+               WHERE lower(o) IN ('security_invoker=true', 'security_invoker=on', 'security_invoker=1')
+           ) THEN 'INVOKER' ELSE 'DEFINER' END,
+           COALESCE((
+               SELECT upper(split_part(o, '=', 2)) FROM unnest(c.reloptions) AS o WHERE o LIKE 'check_option=%'
+           ), 'NONE'),
+           (pg_catalog.pg_relation_is_updatable(c.oid, false) & 20) = 20,
+           COALESCE(pg_catalog.obj_description(c.oid, 'pg_class'), '')
+    FROM pg_catalog.pg_class AS c
+    JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+    WHERE c.relkind = 'v' AND n.nspname = ANY($1)
+    ORDER BY n.nspname, c.relname
+    `
+	rows, err := db.QueryContext(qctx, query, pq.Array(schemas))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var oids []int64
+	for rows.Next() {
+		view := new(sql_model.View)
+		var oid int64
+		err := rows.Scan(&oid, &view.Schema, &view.Name, &view.Definition, &view.SecurityType, &view.CheckOption,
+			&view.IsUpdatable, &view.Comment)
+		if err != nil {
+			return nil, err
+		}
+		view.Name = qualifiedName(view.Schema, view.Name, len(schemas) > 1)
+		if filter.AcceptTable(view.Name) {
+			views = append(views, view)
+			oids = append(oids, oid)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, view := range views {
+		// カラム情報の取得
+		if view.Columns, err = getColumns(ctx, db, oids[i]); err != nil {
+			return nil, err
+		}
+		// 参照テーブルの取得
+		if view.ReferencedTables, err = getViewReferences(ctx, db, oids[i], view.Schema, len(schemas) > 1); err != nil {
+			return nil, err
+		}
+	}
+
+	return views, nil
+}
+
+// getViewReferences はビューの書き換えルールの依存関係から参照しているテーブル・ビューを取得します。
+func getViewReferences(ctx context.Context, db inspector.Querier, viewOid int64, schema string, qualify bool) ([]string, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var references []string
+
+	query := `
+    SELECT DISTINCT rn.nspname, rc.relname
+    FROM pg_catalog.pg_rewrite AS r
+    JOIN pg_catalog.pg_depend AS d ON d.classid = 'pg_catalog.pg_rewrite'::regclass AND d.objid = r.oid
+    JOIN pg_catalog.pg_class AS rc ON rc.oid = d.refobjid
+    JOIN pg_catalog.pg_namespace AS rn ON rn.oid = rc.relnamespace
+    WHERE r.ev_class = $1 AND d.refobjid <> $1 AND rc.relkind IN ('r', 'p', 'v', 'm', 'f')
+    ORDER BY rn.nspname, rc.relname
+    `
+	rows, err := db.QueryContext(ctx, query, viewOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var refSchema, refName string
+		if err := rows.Scan(&refSchema, &refName); err != nil {
+			return nil, err
+		}
+		references = append(references, qualifiedName(refSchema, refName, qualify || refSchema != schema))
+	}
+
+	return references, rows.Err()
+}
+
+func getSequences(ctx context.Context, db inspector.Querier, schemas []string) ([]*sql_model.Sequence, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var sequences []*sql_model.Sequence
+
+	// シーケンス一覧の取得（SERIAL列やIDENTITY列が所有するシーケンスを含む）
+	query := `
+    SELECT n.nspname, c.relname, pg_catalog.format_type(s.seqtypid, NULL),
+           s.seqstart, s.seqincrement, s.seqmin, s.seqmax, s.seqcycle,
+           COALESCE(tc.relname || '.' || ta.attname, '')
+    FROM pg_catalog.pg_sequence AS s
+    JOIN pg_catalog.pg_class AS c ON c.oid = s.seqrelid
+    JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+    LEFT JOIN pg_catalog.pg_depend AS d
+        ON d.objid = c.oid AND d.classid = 'pg_catalog.pg_class'::regclass
+        AND d.refclassid = 'pg_catalog.pg_class'::regclass AND d.deptype IN ('a', 'i')
+    LEFT JOIN pg_catalog.pg_class AS tc ON tc.oid = d.refobjid
+    LEFT JOIN pg_catalog.pg_attribute AS ta ON ta.attrelid = d.refobjid AND ta.attnum = d.refobjsubid
+    WHERE n.nspname = ANY($1)
+    ORDER BY n.nspname, c.relname
+    `
+	rows, err := db.QueryContext(ctx, query, pq.Array(schemas))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		seq := new(sql_model.Sequence)
+		err := rows.Scan(&seq.Schema, &seq.Name, &seq.DataType, &seq.Start, &seq.Increment,
+			&seq.MinValue, &seq.MaxValue, &seq.Cycle, &seq.OwnedBy)
+		if err != nil {
+			return nil, err
+		}
+		sequences = append(sequences, seq)
+	}
+
+	return sequences, rows.Err()
+}
+
+// qualifiedName は複数スキーマを扱う場合に「スキーマ名.テーブル名」の形式で名前を返します。
+func qualifiedName(schema, name string, qualify bool) string {
+	if qualify {
+		return schema + "." + name
+	}
+	return name
+}
+
+func findColumn(columns []*sql_model.Column, name string) *sql_model.Column {
+	for _, col := range columns {
+		if col.Name == name {
+			return col
+		}
+	}
+	return nil
+}
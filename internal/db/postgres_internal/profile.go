@@ -0,0 +1,56 @@
+package postgres_internal
+
+import (
+	"context"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"fmt"
+	"github.com/lib/pq"
+	"strings"
+)
+
+// Profile はテーブルのデータをサンプリングし、カラムの値の傾向を調べます。
+// 推定行数がサンプリングする行数より多いテーブルは TABLESAMPLE SYSTEM でページ単位に間引いてから読み取ります。
+// テーブルは DB_WORKERS 個のワーカーで並列に調べます。
+func (i *Inspector) Profile(ctx context.Context, dbInfos []*sql_model.DB) error {
+	db, err := i.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	opts := i.cfg.ProfileOptions()
+	for _, dbInfo := range dbInfos {
+		err := inspector.ForEach(ctx, i.cfg.Workers, len(dbInfo.Tables), func(ctx context.Context, j int) error {
+			table := dbInfo.Tables[j]
+			if err := inspector.ProfileTable(ctx, db, table, opts, sampleQuery); err != nil {
+				return fmt.Errorf("could not profile table %s: %w", table.Name, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sampleQuery はテーブルから最大 limit 行を取得するSELECT文を返します。
+// ページ単位のサンプリングは行の偏りが大きいため、必要な行数の2倍を目安にページを選びます。
+func sampleQuery(table *sql_model.Table, columns []*sql_model.Column, limit int) string {
+	names := make([]string, len(columns))
+	for j, col := range columns {
+		names[j] = pq.QuoteIdentifier(col.Name)
+	}
+	// 複数スキーマを取得した場合のテーブル名はスキーマ名で修飾されている
+	name := strings.TrimPrefix(table.Name, table.Schema+".")
+	from := pq.QuoteIdentifier(table.Schema) + "." + pq.QuoteIdentifier(name)
+
+	if table.TableRows != nil && *table.TableRows > int64(limit) {
+		percent := float64(limit) * 2 / float64(*table.TableRows) * 100
+		if percent < 100 {
+			from += fmt.Sprintf(" TABLESAMPLE SYSTEM (%g)", percent)
+		}
+	}
+	return fmt.Sprintf("SELECT %s FROM %s LIMIT %d", strings.Join(names, ", "), from, limit)
+}
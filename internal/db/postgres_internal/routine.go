@@ -0,0 +1,163 @@
+package postgres_internal
+
+import (
+	"context"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"github.com/lib/pq"
+	"strings"
+)
+
+// getRoutines は対象スキーマのファンクション・プロシージャとその引数を取得します。
+// 集約関数・ウィンドウ関数と、拡張機能（CREATE EXTENSION）が作成した関数は対象外とします。
+func getRoutines(ctx context.Context, db inspector.Querier, schemas []string) ([]*sql_model.Routine, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var routines []*sql_model.Routine
+
+	query := `
+    SELECT p.oid, n.nspname, p.proname, CASE p.prokind WHEN 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END,
+           CASE p.prokind WHEN 'p' THEN '' ELSE COALESCE(pg_catalog.pg_get_function_result(p.oid), '') END,
+           upper(l.lanname), COALESCE(p.prosrc, ''), CASE WHEN p.prosecdef THEN 'DEFINER' ELSE 'INVOKER' END,
+           p.provolatile = 'i', COALESCE(pg_catalog.obj_description(p.oid, 'pg_proc'), '')
+    FROM pg_catalog.pg_proc AS p
+    JOIN pg_catalog.pg_namespace AS n ON n.oid = p.pronamespace
+    JOIN pg_catalog.pg_language AS l ON l.oid = p.prolang
+    WHERE n.nspname = ANY($1) AND p.prokind IN ('f', 'p')
+      AND NOT EXISTS (
+          SELECT 1 FROM pg_catalog.pg_depend AS d
+          WHERE d.classid = 'pg_catalog.pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+      )
+    ORDER BY n.nspname, p.prokind DESC, p.proname, p.oid
+    `
+	rows, err := db.QueryContext(ctx, query, pq.Array(schemas))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byOid := make(map[int64]*sql_model.Routine)
+	for rows.Next() {
+		routine := new(sql_model.Routine)
+		var oid int64
+		err := rows.Scan(&oid, &routine.Schema, &routine.Name, &routine.Type, &routine.ReturnType, &routine.Language,
+			&routine.Definition, &routine.SecurityType, &routine.IsDeterministic, &routine.Comment)
+		if err != nil {
+			return nil, err
+		}
+		routine.Name = qualifiedName(routine.Schema, routine.Name, len(schemas) > 1)
+		routines = append(routines, routine)
+		byOid[oid] = routine
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 引数の取得（OUT 引数を含む場合は proallargtypes、それ以外は proargtypes に型が入る）
+	query = `
+    SELECT p.oid, COALESCE(p.proargnames[k.ord], ''), COALESCE(p.proargmodes[k.ord], 'i'),
+           pg_catalog.format_type(k.typ, NULL)
+    FROM pg_catalog.pg_proc AS p
+    JOIN pg_catalog.pg_namespace AS n ON n.oid = p.pronamespace
+    CROSS JOIN LATERAL unnest(COALESCE(p.proallargtypes, p.proargtypes::oid[])) WITH ORDINALITY AS k(typ, ord)
+    WHERE n.nspname = ANY($1) AND p.prokind IN ('f', 'p')
+    ORDER BY p.oid, k.ord
+    `
+	rows, err = db.QueryContext(ctx, query, pq.Array(schemas))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var oid int64
+		var mode string
+		param := new(sql_model.Parameter)
+		if err := rows.Scan(&oid, &param.Name, &mode, &param.DataType); err != nil {
+			return nil, err
+		}
+		routine, ok := byOid[oid]
+		if !ok {
+			continue
+		}
+		param.Mode = parameterMode(mode)
+		routine.Parameters = append(routine.Parameters, param)
+	}
+
+	return routines, rows.Err()
+}
+
+// parameterMode は pg_proc.proargmodes の値を引数の入出力区分に変換します。
+// RETURNS TABLE の列は OUT として扱います。
+func parameterMode(code string) string {
+	switch code {
+	case "o", "t":
+		return "OUT"
+	case "b":
+		return "INOUT"
+	case "v":
+		return "VARIADIC"
+	default:
+		return "IN"
+	}
+}
+
+// getTriggers はテーブルのトリガーを取得します。外部キー制約などの内部トリガーは対象外とします。
+// PostgreSQLでは同じタイミング・イベントのトリガーは名前順に実行されるため、その順序を実行順とします。
+func getTriggers(ctx context.Context, db inspector.Querier, tableOid int64) ([]*sql_model.Trigger, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var triggers []*sql_model.Trigger
+
+	query := `
+    SELECT t.tgname, t.tgtype, pg_catalog.pg_get_triggerdef(t.oid, true)
+    FROM pg_catalog.pg_trigger AS t
+    WHERE t.tgrelid = $1 AND NOT t.tgisinternal
+    ORDER BY t.tgname
+    `
+	rows, err := db.QueryContext(ctx, query, tableOid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make(map[string]int64)
+	for rows.Next() {
+		trigger := new(sql_model.Trigger)
+		var tgtype int64
+		if err := rows.Scan(&trigger.Name, &tgtype, &trigger.Statement); err != nil {
+			return nil, err
+		}
+		trigger.Timing, trigger.Event = triggerType(tgtype)
+		orders[trigger.Timing+" "+trigger.Event]++
+		trigger.Order = orders[trigger.Timing+" "+trigger.Event]
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers, rows.Err()
+}
+
+// triggerType は pg_trigger.tgtype のビットから起動タイミングとイベントを求めます。
+func triggerType(tgtype int64) (string, string) {
+	timing := "AFTER"
+	switch {
+	case tgtype&(1<<1) != 0:
+		timing = "BEFORE"
+	case tgtype&(1<<6) != 0:
+		timing = "INSTEAD OF"
+	}
+
+	var events []string
+	for _, e := range []struct {
+		bit  int64
+		name string
+	}{{1 << 2, "INSERT"}, {1 << 4, "UPDATE"}, {1 << 3, "DELETE"}, {1 << 5, "TRUNCATE"}} {
+		if tgtype&e.bit != 0 {
+			events = append(events, e.name)
+		}
+	}
+
+	return timing, strings.Join(events, " OR ")
+}
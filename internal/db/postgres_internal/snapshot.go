@@ -0,0 +1,138 @@
+package postgres_internal
+
+import (
+	"context"
+	"database/sql"
+	"export-db-info/internal/db/inspector"
+	"fmt"
+	"github.com/lib/pq"
+	"log"
+	"strings"
+)
+
+// begin はテーブルごとの情報を並列に取得するための接続を DB_WORKERS 個用意します。
+// スナップショットから取得する場合は、REPEATABLE READ の読み取り専用トランザクションを接続ごとに開始し、
+// 最初のトランザクションのスナップショットを pg_export_snapshot でエクスポートして残りのトランザクションで共有します。
+// スナップショットをエクスポートできない場合（スタンバイサーバーなど）は1つのトランザクションで順に取得します。
+// 問い合わせに使う接続と、取得後にトランザクションを終了する関数を返します。
+func (i *Inspector) begin(ctx context.Context, db *sql.DB) (*inspector.QuerierPool, func(), error) {
+	workers := i.cfg.Workers
+	if workers <= 0 {
+		workers = inspector.DefaultWorkers
+	}
+
+	if !i.cfg.Snapshot {
+		queriers := make([]inspector.Querier, workers)
+		for j := range queriers {
+			queriers[j] = db
+		}
+		return inspector.NewQuerierPool(queriers...), func() {}, nil
+	}
+
+	var txs []*sql.Tx
+	end := func() {
+		for _, tx := range txs {
+			tx.Rollback()
+		}
+	}
+	beginTx := func() (*sql.Tx, error) {
+		tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+		return tx, nil
+	}
+
+	tx, err := beginTx()
+	if err != nil {
+		return nil, nil, err
+	}
+	queriers := []inspector.Querier{tx}
+	if workers > 1 {
+		var snapshot string
+		if err := tx.QueryRowContext(ctx, "SELECT pg_catalog.pg_export_snapshot()").Scan(&snapshot); err != nil {
+			// エラーでトランザクションが中断されるため、トランザクションを開始し直す
+			log.Printf("could not export snapshot, querying on one connection: %v", err)
+			end()
+			txs = nil
+			if tx, err = beginTx(); err != nil {
+				return nil, nil, err
+			}
+			return inspector.NewQuerierPool(tx), end, nil
+		}
+		for j := 1; j < workers; j++ {
+			tx, err := beginTx()
+			if err == nil {
+				_, err = tx.ExecContext(ctx, "SET TRANSACTION SNAPSHOT "+pq.QuoteLiteral(snapshot))
+			}
+			if err != nil {
+				end()
+				return nil, nil, err
+			}
+			queriers = append(queriers, tx)
+		}
+	}
+	return inspector.NewQuerierPool(queriers...), end, nil
+}
+
+// checkReadOnlyUser は接続ユーザーがスーパーユーザーの場合、または対象スキーマへの書き込み権限
+// （スキーマの CREATE、テーブルの INSERT・UPDATE・DELETE・TRUNCATE）を持つ場合にエラーを返します。
+// PostgreSQL 14 以前では public スキーマの CREATE が PUBLIC に付与されているため、スキーマの CREATE は
+// スキーマの所有者であるか、接続ユーザー（または所属するロール）に直接付与されている場合のみ書き込み権限とみなします。
+func (i *Inspector) checkReadOnlyUser(ctx context.Context, db inspector.Querier, schemas []string) error {
+	if i.cfg.AllowWrite {
+		return nil
+	}
+
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	query := `
+    SELECT current_user, 'SUPERUSER'
+    FROM pg_catalog.pg_roles
+    WHERE rolname = current_user AND rolsuper
+    UNION ALL
+    SELECT current_user, 'CREATE ON SCHEMA ' || n.nspname
+    FROM pg_catalog.pg_namespace AS n
+    WHERE n.nspname = ANY($1) AND (
+        pg_has_role(n.nspowner, 'USAGE')
+        OR EXISTS (
+            SELECT 1
+            FROM aclexplode(n.nspacl) AS a
+            WHERE a.privilege_type = 'CREATE' AND a.grantee <> 0 AND pg_has_role(a.grantee, 'USAGE')
+        )
+    )
+    UNION ALL
+    SELECT current_user, p.privilege || ' ON ' || n.nspname || '.' || c.relname
+    FROM pg_catalog.pg_class AS c
+    JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+    CROSS JOIN unnest(ARRAY['INSERT', 'UPDATE', 'DELETE', 'TRUNCATE']) AS p(privilege)
+    WHERE n.nspname = ANY($1) AND c.relkind IN ('r', 'p') AND has_table_privilege(c.oid, p.privilege)
+    LIMIT 10
+    `
+	rows, err := db.QueryContext(ctx, query, pq.Array(schemas))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var currentUser string
+	var granted []string
+	for rows.Next() {
+		var privilege string
+		if err := rows.Scan(&currentUser, &privilege); err != nil {
+			return err
+		}
+		granted = append(granted, privilege)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(granted) > 0 {
+		return fmt.Errorf("user %s has write privileges (%s); set DB_ALLOW_WRITE_USER=true to run anyway",
+			currentUser, strings.Join(granted, ", "))
+	}
+	return nil
+}
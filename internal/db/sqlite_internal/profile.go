@@ -0,0 +1,53 @@
+package sqlite_internal
+
+import (
+	"context"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"export-db-info/pkg/db/sqlite"
+	"fmt"
+	"strings"
+)
+
+// Profile はテーブルのデータをサンプリングし、カラムの値の傾向を調べます。
+// SQLite には TABLESAMPLE がないため、テーブルごとに LIMIT で指定した行数までを読み取ります。
+func (i *Inspector) Profile(ctx context.Context, dbInfos []*sql_model.DB) error {
+	db, err := sqlite.Connect(i.cfg.Path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.PingContext(ctx); err != nil {
+		return err
+	}
+
+	opts := i.cfg.ProfileOptions()
+	for _, dbInfo := range dbInfos {
+		err := inspector.ForEach(ctx, i.cfg.Workers, len(dbInfo.Tables), func(ctx context.Context, j int) error {
+			table := dbInfo.Tables[j]
+			if err := inspector.ProfileTable(ctx, db, table, opts, sampleQuery); err != nil {
+				return fmt.Errorf("could not profile table %s: %w", table.Name, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sampleQuery はテーブルから最大 limit 行を取得するSELECT文を返します。
+func sampleQuery(table *sql_model.Table, columns []*sql_model.Column, limit int) string {
+	names := make([]string, len(columns))
+	for j, col := range columns {
+		names[j] = quoteIdentifier(col.Name)
+	}
+	return fmt.Sprintf("SELECT %s FROM %s LIMIT %d", strings.Join(names, ", "), quoteIdentifier(table.Name), limit)
+}
+
+// quoteIdentifier は識別子をダブルクォートで囲みます。
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,802 @@
+package sqlite_internal
+
+import (
+	"context"
+	"database/sql"
+	"export-db-info/internal/db/inspector"
+	"export-db-info/internal/model/sql_model"
+	"export-db-info/pkg/db/sqlite"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	inspector.Register("sqlite", NewInspector)
+}
+
+// Inspector はSQLiteのsqlite_masterとPRAGMAからスキーマ情報を取得します。
+type Inspector struct {
+	cfg    *inspector.Config
+	filter *inspector.Filter // テーブル単位の問い合わせの前に対象のテーブルを絞り込む
+}
+
+// NewInspector は接続設定からSQLite用のSchemaInspectorを生成します。
+func NewInspector(cfg *inspector.Config) (inspector.SchemaInspector, error) {
+	filter, err := inspector.NewFilter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Inspector{cfg: cfg, filter: filter}, nil
+}
+
+// Inspect はデータベースファイルを開き、テーブル・ビューとカラムの情報を取得します。
+func (i *Inspector) Inspect(ctx context.Context) (*sql_model.DB, error) {
+	// データベースに接続
+	db, err := sqlite.Connect(i.cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// データベースファイルは読み取り専用で開くため、書き込み権限の確認は行わない
+	q, end, err := i.begin(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	// 各テーブルとそのカラム情報を取得
+	tables, err := getTables(ctx, q, i.filter, i.cfg.QueryWorkers())
+	if err != nil {
+		return nil, err
+	}
+
+	// ビューとそのカラム情報を取得
+	views, err := getViews(ctx, q, i.filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// データベース名はファイル名（拡張子なし）とする
+	name := strings.TrimSuffix(filepath.Base(i.cfg.Path), filepath.Ext(i.cfg.Path))
+
+	return &sql_model.DB{Name: name, Tables: tables, Views: views}, nil
+}
+
+// begin はスナップショットから取得する場合に、専用の接続でトランザクションを開始します。
+// 最初の読み取りで取得した共有ロック（WALモードではスナップショット）をトランザクションの終了まで保持するため、
+// 取得中に他のプロセスが行った変更は反映されません。
+func (i *Inspector) begin(ctx context.Context, db *sql.DB) (inspector.Querier, func(), error) {
+	if !i.cfg.Snapshot {
+		return db, func() {}, nil
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := conn.ExecContext(ctx, "BEGIN"); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, func() {
+		conn.ExecContext(context.Background(), "ROLLBACK")
+		conn.Close()
+	}, nil
+}
+
+// getTables はテーブルの一覧を取得した後、filter で除外したものを除くテーブルごとの情報を workers 個のワーカーで並列に取得します。
+// 結果はテーブル名の順に返します。
+func getTables(ctx context.Context, db inspector.Querier, filter *inspector.Filter, workers int) ([]*sql_model.Table, error) {
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	// テーブル一覧の取得（SQLite内部テーブルは除外）
+	rows, err := db.QueryContext(qctx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tableNames []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, err
+		}
+		if filter.AcceptTable(tableName) {
+			tableNames = append(tableNames, tableName)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	tables := make([]*sql_model.Table, len(tableNames))
+	err = inspector.ForEach(ctx, workers, len(tableNames), func(ctx context.Context, i int) error {
+		table, err := getTable(ctx, db, tableNames[i])
+		if err != nil {
+			return err
+		}
+		tables[i] = table
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// getTable は1つのテーブルのカラム・インデックス・外部キー・CHECK制約・トリガーを取得します。
+func getTable(ctx context.Context, db inspector.Querier, tableName string) (*sql_model.Table, error) {
+	// カラム情報の取得
+	columns, err := getColumns(ctx, db, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// インデックス情報の取得
+	indexes, err := getIndexes(ctx, db, tableName, columns)
+	if err != nil {
+		return nil, err
+	}
+	applyIndexInfo(indexes, columns)
+
+	// 外部キー制約の取得
+	foreignKeys, err := getForeignKeys(ctx, db, tableName)
+	if err != nil {
+		return nil, err
+	}
+	applyForeignKeyInfo(foreignKeys, columns)
+
+	// 生成列の式・照合順序とCHECK制約の取得
+	var definition string
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+	if err := db.QueryRowContext(qctx, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", tableName).Scan(&definition); err != nil {
+		return nil, err
+	}
+	tableDef := parseTableDefinition(definition)
+	tableDef.apply(columns)
+
+	// トリガーの取得
+	triggers, err := getTriggers(ctx, db, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sql_model.Table{
+		Name:        tableName,
+		Columns:     columns,
+		Indexes:     indexes,
+		ForeignKeys: foreignKeys,
+		Checks:      tableDef.checks,
+		Triggers:    triggers,
+	}, nil
+}
+
+// triggerDefinitionPattern は CREATE TRIGGER 文から起動タイミング・イベントと、WHEN 句を含むトリガーの本体を取り出します。
+var triggerDefinitionPattern = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:TEMP\s+|TEMPORARY\s+)?TRIGGER\s+.*?\s` +
+	`(BEFORE\s+|AFTER\s+|INSTEAD\s+OF\s+)?(DELETE|INSERT|UPDATE)\b.*?\sON\s+\S+\s+(?:FOR\s+EACH\s+ROW\s+)?(.*?);?\s*$`)
+
+// getTriggers はテーブルのトリガーを取得します。
+// SQLiteでは同じタイミング・イベントのトリガーの実行順が保証されないため、作成順（rowid順）を実行順とします。
+func getTriggers(ctx context.Context, db inspector.Querier, tableName string) ([]*sql_model.Trigger, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var triggers []*sql_model.Trigger
+
+	rows, err := db.QueryContext(ctx, "SELECT name, sql FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ? ORDER BY rowid", tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make(map[string]int64)
+	for rows.Next() {
+		trigger := new(sql_model.Trigger)
+		var definition string
+		if err := rows.Scan(&trigger.Name, &definition); err != nil {
+			return nil, err
+		}
+
+		// 起動タイミングの省略時は BEFORE となる
+		trigger.Timing, trigger.Statement = "BEFORE", definition
+		if m := triggerDefinitionPattern.FindStringSubmatch(definition); m != nil {
+			if m[1] != "" {
+				trigger.Timing = strings.ToUpper(strings.Join(strings.Fields(m[1]), " "))
+			}
+			trigger.Event = strings.ToUpper(m[2])
+			trigger.Statement = m[3]
+		}
+		orders[trigger.Timing+" "+trigger.Event]++
+		trigger.Order = orders[trigger.Timing+" "+trigger.Event]
+
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers, rows.Err()
+}
+
+var (
+	viewDefinitionPattern = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:TEMP\s+|TEMPORARY\s+)?VIEW\s+.*?\bAS\s+(.*?);?\s*$`)
+	tableReferencePattern = regexp.MustCompile("(?i)\\b(?:FROM|JOIN)\\s+[\\s(]*[\"`\\[]?([\\w$]+)")
+)
+
+func getViews(ctx context.Context, db inspector.Querier, filter *inspector.Filter) ([]*sql_model.View, error) {
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var views []*sql_model.View
+
+	// ビュー一覧の取得
+	rows, err := db.QueryContext(qctx, "SELECT name, sql FROM sqlite_master WHERE type = 'view' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		view := new(sql_model.View)
+		var definition string
+		if err := rows.Scan(&view.Name, &definition); err != nil {
+			return nil, err
+		}
+		// sqlite_master には CREATE VIEW 文全体が保存されているため、AS 以降のSELECT文を取り出す
+		view.Definition = definition
+		if m := viewDefinitionPattern.FindStringSubmatch(definition); m != nil {
+			view.Definition = m[1]
+		}
+		if filter.AcceptTable(view.Name) {
+			views = append(views, view)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, view := range views {
+		// カラム情報の取得
+		if view.Columns, err = getColumns(ctx, db, view.Name); err != nil {
+			return nil, err
+		}
+		// 参照テーブルの取得
+		if view.ReferencedTables, err = getViewReferences(ctx, db, view.Definition); err != nil {
+			return nil, err
+		}
+	}
+
+	return views, nil
+}
+
+// getViewReferences はビューのSELECT文の FROM / JOIN 句に現れるテーブル・ビューを取得します。
+// SQLiteは依存関係を保持していないため、定義文中の名前のうち実在するものを参照テーブルとみなします。
+func getViewReferences(ctx context.Context, db inspector.Querier, definition string) ([]string, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var references []string
+	seen := make(map[string]bool)
+	for _, m := range tableReferencePattern.FindAllStringSubmatch(definition, -1) {
+		name := m[1]
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		var actualName string
+		err := db.QueryRowContext(ctx, "SELECT name FROM sqlite_master WHERE type IN ('table', 'view') AND name = ? COLLATE NOCASE", name).Scan(&actualName)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		references = append(references, actualName)
+	}
+	return references, nil
+}
+
+func getColumns(ctx context.Context, db inspector.Querier, tableName string) ([]*sql_model.Column, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var columns []*sql_model.Column
+
+	// カラム情報の取得
+	// pragma_table_xinfo は生成列も返す（hidden: 0 = 通常の列, 2 = VIRTUAL の生成列, 3 = STORED の生成列）
+	rows, err := db.QueryContext(ctx, `SELECT name, type, "notnull", dflt_value, pk, hidden FROM pragma_table_xinfo(?)
+		WHERE hidden IN (0, 2, 3) ORDER BY cid`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		col := new(sql_model.Column)
+		var notNull bool
+		var defaultVal sql.NullString
+		var pk, hidden int
+		if err := rows.Scan(&col.Name, &col.Type, &notNull, &defaultVal, &pk, &hidden); err != nil {
+			return nil, err
+		}
+
+		if defaultVal.Valid {
+			col.Default, col.IsDefaultExpression = parseDefault(defaultVal.String)
+		}
+		col.IsNullable = !notNull
+		col.IsPrimaryKey = pk > 0
+		// 主キーはrowidまたは自動作成されるインデックスで索引付けされる
+		col.IsIndexed = pk > 0
+		switch hidden {
+		case 2:
+			col.Generated = "VIRTUAL"
+		case 3:
+			col.Generated = "STORED"
+		}
+
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+// getIndexes は PRAGMA index_list / index_xinfo からインデックス情報を取得します。
+// INTEGER PRIMARY KEY はrowidそのものでインデックスが作成されないため、主キーのインデックスとして補います。
+func getIndexes(ctx context.Context, db inspector.Querier, tableName string, columns []*sql_model.Column) ([]*sql_model.Index, error) {
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(qctx, `
+    SELECT l.name, l."unique", l.origin, COALESCE(m.sql, '')
+    FROM pragma_index_list(?) AS l
+    LEFT JOIN sqlite_master AS m ON m.type = 'index' AND m.name = l.name
+    ORDER BY l.origin <> 'pk', l.name
+    `, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type indexInfo struct {
+		index            *sql_model.Index
+		name, definition string
+	}
+	var infos []indexInfo
+	for rows.Next() {
+		info := indexInfo{index: &sql_model.Index{Type: "BTREE", IsVisible: true}}
+		var origin string
+		if err := rows.Scan(&info.name, &info.index.IsUnique, &origin, &info.definition); err != nil {
+			return nil, err
+		}
+		info.index.Name = info.name
+		if origin == "pk" {
+			// 自動作成されるインデックス名（sqlite_autoindex_...）は意味を持たないため他のDBに合わせる
+			info.index.Name = "PRIMARY"
+			info.index.IsPrimary = true
+		}
+		infos = append(infos, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var indexes []*sql_model.Index
+	if len(infos) == 0 || !infos[0].index.IsPrimary {
+		if pk := rowidPrimaryKey(columns); pk != nil {
+			indexes = append(indexes, pk)
+		}
+	}
+	for _, info := range infos {
+		definition := parseIndexDefinition(info.definition)
+		info.index.Condition = definition.condition
+		if info.index.Columns, err = getIndexColumns(ctx, db, info.name, definition.parts); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, info.index)
+	}
+
+	return indexes, nil
+}
+
+// rowidPrimaryKey は INTEGER PRIMARY KEY のカラムから主キーのインデックスを組み立てます。
+func rowidPrimaryKey(columns []*sql_model.Column) *sql_model.Index {
+	for _, col := range columns {
+		if col.IsPrimaryKey {
+			return &sql_model.Index{
+				Name:      "PRIMARY",
+				Columns:   []*sql_model.IndexColumn{{Name: col.Name}},
+				IsPrimary: true,
+				IsUnique:  true,
+				Type:      "BTREE",
+				IsVisible: true,
+			}
+		}
+	}
+	return nil
+}
+
+func getIndexColumns(ctx context.Context, db inspector.Querier, indexName string, parts []string) ([]*sql_model.IndexColumn, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var indexColumns []*sql_model.IndexColumn
+
+	// key = 0 の行はrowidなどインデックスのキーではない補助カラム
+	rows, err := db.QueryContext(ctx, `SELECT seqno, name, "desc" FROM pragma_index_xinfo(?) WHERE "key" = 1 ORDER BY seqno`, indexName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var seqno int
+		// 式インデックスの場合カラム名はNULLになる
+		var columnName sql.NullString
+		part := new(sql_model.IndexColumn)
+		if err := rows.Scan(&seqno, &columnName, &part.Descending); err != nil {
+			return nil, err
+		}
+		part.Name = columnName.String
+		if !columnName.Valid && seqno < len(parts) {
+			part.Expression = parts[seqno]
+		}
+		indexColumns = append(indexColumns, part)
+	}
+
+	return indexColumns, rows.Err()
+}
+
+// indexDefinition は CREATE INDEX 文から取り出したキー部分と部分インデックスの条件です。
+type indexDefinition struct {
+	parts     []string
+	condition string
+}
+
+// parseIndexDefinition は sqlite_master に保存された CREATE INDEX 文からキー部分と WHERE 句を取り出します。
+// PRAGMA では式インデックスの式や部分インデックスの条件を取得できないため、定義文から補います。
+func parseIndexDefinition(definition string) indexDefinition {
+	var result indexDefinition
+	parts, rest := splitDefinition(definition)
+	for _, part := range parts {
+		result.parts = append(result.parts, trimKeyPart(part))
+	}
+	if len(rest) > 5 && strings.EqualFold(rest[:5], "WHERE") {
+		result.condition = strings.TrimSpace(rest[5:])
+	}
+	return result
+}
+
+// splitDefinition は定義文の最初の括弧の中身をトップレベルのカンマで区切り、閉じ括弧より後ろの部分とともに返します。
+// 引用符で囲まれた部分と入れ子の括弧の中のカンマでは区切りません。
+func splitDefinition(definition string) ([]string, string) {
+	start := strings.IndexByte(definition, '(')
+	if start < 0 {
+		return nil, ""
+	}
+
+	var parts []string
+	depth, partStart := 0, start+1
+	var quote byte
+	for i := start; i < len(definition); i++ {
+		c := definition[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			quote = c
+			if c == '[' {
+				quote = ']'
+			}
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(definition[partStart:i]))
+				return parts, strings.TrimSpace(definition[i+1:])
+			}
+		case c == ',' && depth == 1:
+			parts = append(parts, strings.TrimSpace(definition[partStart:i]))
+			partStart = i + 1
+		}
+	}
+	return parts, ""
+}
+
+// maskNested は引用符の中身を「_」に、括弧の中身を空白に置き換えた文字列を返します。
+// 位置は元の文字列と変わらないため、トップレベルのキーワードを探した結果の位置で元の文字列を参照できます。
+func maskNested(s string) string {
+	masked := []byte(s)
+	depth := 0
+	var quote byte
+	for i := 0; i < len(masked); i++ {
+		c := masked[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				masked[i] = '_'
+			}
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			quote = c
+			if c == '[' {
+				quote = ']'
+			}
+		case c == '(':
+			if depth > 0 {
+				masked[i] = ' '
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth > 0 {
+				masked[i] = ' '
+			}
+		default:
+			if depth > 0 {
+				masked[i] = ' '
+			}
+		}
+	}
+	return string(masked)
+}
+
+// numericDefaultPattern は数値リテラル（符号付きを含む）に一致します。
+var numericDefaultPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+
+// parseDefault は pragma_table_info の dflt_value（定義文に書かれたままのデフォルト値）を、値と式かどうかに分けます。
+// 文字列・数値・真偽値のリテラルは引用符を外した値とし、CURRENT_TIMESTAMP や括弧で囲まれた式は式として扱います。
+// DEFAULT NULL はデフォルト値なしとしてnilを返します。
+func parseDefault(value string) (*string, bool) {
+	upper := strings.ToUpper(value)
+	switch {
+	case upper == "NULL":
+		return nil, false
+	case len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0]:
+		quote := value[:1]
+		literal := strings.ReplaceAll(value[1:len(value)-1], quote+quote, quote)
+		return &literal, false
+	case numericDefaultPattern.MatchString(value), upper == "TRUE", upper == "FALSE":
+		return &value, false
+	default:
+		return &value, true
+	}
+}
+
+// tableDefinition は CREATE TABLE 文から取り出したカラムの属性とCHECK制約です。
+// PRAGMA では生成列の式・照合順序・CHECK制約を取得できないため、定義文から補います。
+type tableDefinition struct {
+	expressions   map[string]string // 生成列の式（カラム名は小文字）
+	collations    map[string]string // COLLATE で指定した照合順序（カラム名は小文字）
+	autoIncrement map[string]bool   // AUTOINCREMENT を指定したカラム（カラム名は小文字）
+	checks        []*sql_model.Check
+}
+
+var (
+	// checkPattern は [CONSTRAINT 名前] CHECK ( の位置を探します（maskNested で引用符・括弧の中身を隠した文字列が対象）。
+	checkPattern = regexp.MustCompile("(?i)(?:\\bCONSTRAINT\\s+(\"[^\"]*\"|`[^`]*`|\\[[^\\]]*\\]|\\S+)\\s+)?\\bCHECK\\s*\\(")
+	// generatedPattern は [GENERATED ALWAYS] AS ( の位置を探します。
+	generatedPattern = regexp.MustCompile(`(?i)\bAS\s*\(`)
+	// collatePattern は COLLATE 照合順序 を探します。
+	collatePattern = regexp.MustCompile(`(?i)\bCOLLATE\s+(\S+)`)
+	// autoIncrementPattern は AUTOINCREMENT を探します。
+	autoIncrementPattern = regexp.MustCompile(`(?i)\bAUTOINCREMENT\b`)
+	// tableConstraintPattern はテーブル制約の先頭のキーワードです。
+	tableConstraintPattern = regexp.MustCompile(`(?i)^(CONSTRAINT|PRIMARY|UNIQUE|CHECK|FOREIGN)\b`)
+)
+
+// parseTableDefinition は sqlite_master に保存された CREATE TABLE 文からカラムの属性とCHECK制約を取り出します。
+// カラム定義内のCHECK制約もテーブルのCHECK制約として扱います。
+func parseTableDefinition(definition string) tableDefinition {
+	result := tableDefinition{
+		expressions:   make(map[string]string),
+		collations:    make(map[string]string),
+		autoIncrement: make(map[string]bool),
+	}
+
+	parts, _ := splitDefinition(definition)
+	for _, part := range parts {
+		masked := maskNested(part)
+		for _, m := range checkPattern.FindAllStringSubmatchIndex(masked, -1) {
+			check := &sql_model.Check{IsEnforced: true}
+			if m[2] >= 0 {
+				check.Name = unquoteName(part[m[2]:m[3]])
+			}
+			open := m[1] - 1
+			if end := strings.IndexByte(masked[open:], ')'); end >= 0 {
+				check.Expression = part[open : open+end+1]
+			}
+			result.checks = append(result.checks, check)
+		}
+		if tableConstraintPattern.MatchString(masked) {
+			continue
+		}
+
+		fields := strings.Fields(masked)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(unquoteName(part[:len(fields[0])]))
+		if loc := generatedPattern.FindStringIndex(masked); loc != nil {
+			open := loc[1] - 1
+			if end := strings.IndexByte(masked[open:], ')'); end >= 0 {
+				result.expressions[name] = strings.TrimSpace(part[open+1 : open+end])
+			}
+		}
+		if m := collatePattern.FindStringSubmatchIndex(masked); m != nil {
+			result.collations[name] = unquoteName(part[m[2]:m[3]])
+		}
+		result.autoIncrement[name] = autoIncrementPattern.MatchString(masked)
+	}
+	return result
+}
+
+// apply は定義文から取り出したカラムの属性をカラム情報へ反映します。
+func (d tableDefinition) apply(columns []*sql_model.Column) {
+	for _, col := range columns {
+		name := strings.ToLower(col.Name)
+		if col.Generated != "" {
+			col.Expression = d.expressions[name]
+		}
+		col.Collation = d.collations[name]
+		if d.autoIncrement[name] {
+			col.Extra = "AUTOINCREMENT"
+		}
+	}
+}
+
+// unquoteName は "名前" / `名前` / [名前] の引用符を取り除きます。
+func unquoteName(name string) string {
+	if len(name) >= 2 {
+		switch first, last := name[0], name[len(name)-1]; {
+		case first == '"' && last == '"', first == '`' && last == '`', first == '[' && last == ']', first == '\'' && last == '\'':
+			return name[1 : len(name)-1]
+		}
+	}
+	return name
+}
+
+// trimKeyPart はキー部分から末尾の ASC / DESC を取り除きます。
+func trimKeyPart(part string) string {
+	part = strings.TrimSpace(part)
+	upper := strings.ToUpper(part)
+	for _, suffix := range []string{" ASC", " DESC"} {
+		if strings.HasSuffix(upper, suffix) {
+			return strings.TrimSpace(part[:len(part)-len(suffix)])
+		}
+	}
+	return part
+}
+
+// applyIndexInfo はインデックス定義からカラムのユニーク・インデックス情報を設定します。
+// 部分インデックスのユニーク制約はカラム全体の一意性を保証しないため、インデックスとしてのみ扱います。
+func applyIndexInfo(indexes []*sql_model.Index, columns []*sql_model.Column) {
+	for _, idx := range indexes {
+		for _, part := range idx.Columns {
+			col := findColumn(columns, part.Name)
+			if col == nil {
+				continue
+			}
+			col.IsIndexed = true
+			if idx.IsUnique && !idx.IsPrimary && idx.Condition == "" {
+				col.IsUnique = true
+			}
+		}
+	}
+}
+
+// getForeignKeys は PRAGMA foreign_key_list からテーブルの外部キー制約を取得します。
+// SQLiteは制約名を保持しないため、制約名は空になります。
+func getForeignKeys(ctx context.Context, db inspector.Querier, tableName string) ([]*sql_model.ForeignKey, error) {
+	qctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(qctx, `
+    SELECT id, seq, "table", "from", "to", on_update, on_delete
+    FROM pragma_foreign_key_list(?)
+    ORDER BY id, seq
+    `, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type foreignKeyColumn struct {
+		id, seq    int
+		columnName string
+		fkColumn   sql.NullString
+	}
+	var foreignKeys []*sql_model.ForeignKey
+	var fkColumns [][]foreignKeyColumn
+	lastId := -1
+	for rows.Next() {
+		var c foreignKeyColumn
+		var fkTable, onUpdate, onDelete string
+		if err := rows.Scan(&c.id, &c.seq, &fkTable, &c.columnName, &c.fkColumn, &onUpdate, &onDelete); err != nil {
+			return nil, err
+		}
+
+		// 同じ制約（id）の行をまとめる
+		if c.id != lastId {
+			foreignKeys = append(foreignKeys, &sql_model.ForeignKey{
+				ReferencedTable: fkTable,
+				UpdateRule:      onUpdate,
+				DeleteRule:      onDelete,
+			})
+			fkColumns = append(fkColumns, nil)
+			lastId = c.id
+		}
+		fkColumns[len(fkColumns)-1] = append(fkColumns[len(fkColumns)-1], c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, fk := range foreignKeys {
+		for _, c := range fkColumns[i] {
+			// 参照先カラムが省略されている場合は参照先テーブルの主キーを参照する
+			fkColumn := c.fkColumn.String
+			if !c.fkColumn.Valid {
+				if fkColumn, err = getPrimaryKeyColumn(ctx, db, fk.ReferencedTable, c.seq); err != nil {
+					return nil, err
+				}
+			}
+			fk.Columns = append(fk.Columns, c.columnName)
+			fk.ReferencedColumns = append(fk.ReferencedColumns, fkColumn)
+		}
+	}
+
+	return foreignKeys, nil
+}
+
+// applyForeignKeyInfo は外部キー制約からカラムの参照先情報を設定します。
+// 複数の外部キーに含まれるカラムは、最初の制約の参照先を表示します。
+func applyForeignKeyInfo(foreignKeys []*sql_model.ForeignKey, columns []*sql_model.Column) {
+	for _, fk := range foreignKeys {
+		for i, name := range fk.Columns {
+			col := findColumn(columns, name)
+			if col == nil || col.IsForeign {
+				continue
+			}
+			col.IsForeign = true
+			col.ForeignKeyTable = fk.ReferencedTable
+			col.ForeignKeyColumn = fk.ReferencedColumns[i]
+		}
+	}
+}
+
+func getPrimaryKeyColumn(ctx context.Context, db inspector.Querier, tableName string, seq int) (string, error) {
+	ctx, cancel := inspector.QueryTimeout(ctx)
+	defer cancel()
+
+	var columnName string
+	err := db.QueryRowContext(ctx, "SELECT name FROM pragma_table_info(?) WHERE pk = ?", tableName, seq+1).Scan(&columnName)
+	if err != nil {
+		return "", err
+	}
+	return columnName, nil
+}
+
+func findColumn(columns []*sql_model.Column, name string) *sql_model.Column {
+	for _, col := range columns {
+		if col.Name == name {
+			return col
+		}
+	}
+	return nil
+}
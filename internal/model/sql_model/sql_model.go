@@ -1,28 +1,244 @@
 package sql_model
 
+import "time"
+
 // DB はデータベース全体の情報を保持します。
 type DB struct {
-	Name   string   // データベース名
-	Tables []*Table // データベースに含まれるテーブルのスライス
+	Name         string         // データベース名
+	Tables       []*Table       // データベースに含まれるテーブルのスライス
+	Views        []*View        // データベースに含まれるビューのスライス
+	Sequences    []*Sequence    // データベースに含まれるシーケンスのスライス
+	Routines     []*Routine     // データベースに含まれるストアドプロシージャ・ストアドファンクションのスライス
+	Events       []*Event       // データベースに含まれるイベントのスライス（MySQL のみ）
+	Grants       []*Grant       // データベースに対する権限のスライス（取得した場合のみ）
+	IndexReports []*IndexReport // 使われていない・冗長なインデックスのスライス（調べた場合のみ）
 }
 
 // Table はデータベースのテーブル情報を表します。
 type Table struct {
-	Name    string    // テーブル名
-	Columns []*Column // テーブルのカラム情報
+	Schema          string        // スキーマ名（スキーマを持つDBエンジンのみ）
+	Name            string        // テーブル名
+	Columns         []*Column     // テーブルのカラム情報
+	Indexes         []*Index      // テーブルのインデックス情報（主キーを含む）
+	ForeignKeys     []*ForeignKey // テーブルの外部キー制約
+	Checks          []*Check      // テーブルのCHECK制約
+	Triggers        []*Trigger    // テーブルに定義されたトリガー
+	Partitioning    *Partitioning // パーティション構成（パーティション化されていない場合はnil）
+	Comment         string        // テーブルコメント
+	Engine          string        // ストレージエンジン
+	Collation       string        // 照合順序
+	RowFormat       string        // 行フォーマット
+	AutoIncrement   *int64        // 次のAUTO_INCREMENT値（AUTO_INCREMENT列がない場合はnil）
+	CreateTime      *time.Time    // テーブル作成日時
+	UpdateTime      *time.Time    // テーブル最終更新日時（取得できない場合はnil）
+	TableRows       *int64        // 推定行数（統計情報による概算。取得できない場合はnil）
+	DataLength      *int64        // データのサイズ（バイト）
+	IndexLength     *int64        // インデックスのサイズ（バイト）
+	DataFree        *int64        // 割り当て済みの未使用領域のサイズ（バイト。MySQL のみ）
+	SystemVersioned bool          // システムバージョニングされたテーブルかどうか（MariaDB のみ）
+	Periods         []*Period     // テーブルの期間（システムバージョニングの SYSTEM_TIME を含む）
+}
+
+// Period はテーブルの期間（PERIOD FOR）を表します。
+type Period struct {
+	Name        string // 期間名（システムバージョニングの場合は SYSTEM_TIME）
+	StartColumn string // 期間の開始を表すカラム
+	EndColumn   string // 期間の終了を表すカラム
+}
+
+// View はデータベースのビュー情報を表します。
+type View struct {
+	Schema           string    // スキーマ名（スキーマを持つDBエンジンのみ）
+	Name             string    // ビュー名
+	Columns          []*Column // ビューのカラム情報
+	Definition       string    // ビューを定義するSELECT文
+	SecurityType     string    // 実行時の権限（DEFINER / INVOKER）
+	CheckOption      string    // WITH CHECK OPTION の指定（NONE / LOCAL / CASCADED）
+	IsUpdatable      bool      // 更新可能なビューかどうか
+	ReferencedTables []string  // 参照しているテーブル・ビュー名
+	Comment          string    // コメント
 }
 
 // Column はデータベースのカラム情報を表します。
 type Column struct {
-	Name             string // カラム名
-	Type             string // データ型
-	IsNullable       bool   // NULL値を許容するか
-	Default          string // デフォルト値
-	Comment          string // コメント
-	IsPrimaryKey     bool   // プライマリーキーかどうか
-	IsUnique         bool   // ユニーク制約があるかどうか
-	IsIndexed        bool   // インデックスが貼られているか
-	IsForeign        bool   // インデックスが貼られているか
-	ForeignKeyTable  string // 外部キーとして参照しているテーブル名
-	ForeignKeyColumn string // 外部キーとして参照しているテーブルのカラム名
+	Name                string         // カラム名
+	Type                string         // データ型
+	IsNullable          bool           // NULL値を許容するか
+	Default             *string        // デフォルト値（デフォルト値がない場合・DEFAULT NULL の場合はnil。文字列リテラルは引用符を外した値）
+	IsDefaultExpression bool           // デフォルト値が式かどうか（CURRENT_TIMESTAMP や MySQL 8.0.13 以降の式デフォルトなど）
+	Comment             string         // コメント
+	IsPrimaryKey        bool           // プライマリーキーかどうか
+	IsUnique            bool           // ユニーク制約があるかどうか
+	UniquePrefixLength  int64          // ユニーク制約がカラムの先頭の一部（プレフィックス）のみに対する場合のプレフィックス長（カラム全体の場合は0）
+	IsIndexed           bool           // インデックスが貼られているか
+	IsForeign           bool           // 外部キーかどうか
+	ForeignKeyTable     string         // 外部キーとして参照しているテーブル名
+	ForeignKeyColumn    string         // 外部キーとして参照しているテーブルのカラム名
+	Identity            string         // IDENTITY列の生成方式（ALWAYS / BY DEFAULT）
+	Extra               string         // auto_increment / on update CURRENT_TIMESTAMP などの付加情報（MySQL の EXTRA と同じ形式）
+	Generated           string         // 生成列の種類（VIRTUAL / STORED、生成列でない場合は空）
+	Expression          string         // 生成列の式
+	CharacterSet        string         // 文字セット（文字列型のカラムのみ）
+	Collation           string         // 照合順序（文字列型のカラムのみ）
+	EnumValues          []string       // ENUM / SET 型の値の一覧
+	Profile             *ColumnProfile // 値の傾向（DB_PROFILE を指定した場合のみ。調べていない場合はnil）
+}
+
+// ColumnProfile はテーブルのデータをサンプリングして調べたカラムの値の傾向を表します。
+type ColumnProfile struct {
+	SampledRows   int64         // 調べた行数
+	IsPartial     bool          // 時間の上限に達し、取得できた行までで集計したかどうか
+	NullRatio     float64       // NULLの割合（0〜1）
+	DistinctCount int64         // 異なる値の数の推定値（NULLを除く）
+	Min           string        // 最小値（数値として比較できる場合は数値の順、それ以外は文字列の順。数値と数値以外が混在する場合は数値を小さいとみなす）
+	Max           string        // 最大値
+	AvgLength     float64       // NULLを除いた値の平均の長さ（文字数）
+	TopValues     []*ValueCount // 出現回数の多い値（多い順）
+}
+
+// ValueCount はカラムの値とサンプル内での出現回数の組です。
+type ValueCount struct {
+	Value string // 値
+	Count int64  // 出現回数
+}
+
+// Check はテーブルのCHECK制約を表します。
+type Check struct {
+	Name       string // 制約名（名前のない制約の場合は空）
+	Expression string // 検査する式
+	IsEnforced bool   // 制約が有効かどうか（MySQL の NOT ENFORCED の場合はfalse）
+}
+
+// Index はテーブルのインデックス情報を表します。
+type Index struct {
+	Name      string         // インデックス名（主キーの場合は PRIMARY）
+	Columns   []*IndexColumn // インデックスを構成するカラム（定義順）
+	IsPrimary bool           // 主キーかどうか
+	IsUnique  bool           // ユニークインデックスかどうか
+	Type      string         // インデックスの種類（BTREE / HASH / FULLTEXT / SPATIAL など）
+	IsVisible bool           // オプティマイザから利用可能か（MySQL 8.0 の不可視インデックスの場合はfalse）
+	Comment   string         // コメント
+	Condition string         // 部分インデックスの条件式（PostgreSQL / SQLite のみ）
+}
+
+// IndexColumn はインデックスを構成するカラムを表します。
+type IndexColumn struct {
+	Name       string // カラム名（関数インデックスの場合は空）
+	Expression string // 関数インデックスの式
+	Length     int64  // プレフィックス長（カラム全体の場合は0）
+	Descending bool   // 降順かどうか
+}
+
+// ForeignKey はテーブルの外部キー制約を表します。複合外部キーのカラムは定義順に対応します。
+type ForeignKey struct {
+	Name              string   // 制約名
+	Columns           []string // 外部キーのカラム
+	ReferencedTable   string   // 参照先テーブル名
+	ReferencedColumns []string // 参照先カラム名
+	UpdateRule        string   // ON UPDATE の動作（CASCADE / SET NULL / SET DEFAULT / RESTRICT / NO ACTION）
+	DeleteRule        string   // ON DELETE の動作
+}
+
+// Partitioning はテーブルのパーティション構成を表します。
+type Partitioning struct {
+	Method                 string       // パーティション方式（RANGE / LIST / HASH / KEY / RANGE COLUMNS / LINEAR HASH など）
+	Expression             string       // パーティション式（COLUMNS / KEY の場合はカラムリスト）
+	SubpartitionMethod     string       // サブパーティション方式（サブパーティションがない場合は空）
+	SubpartitionExpression string       // サブパーティション式
+	Partitions             []*Partition // パーティション（定義順）
+}
+
+// Partition はパーティション（またはサブパーティション）を表します。
+type Partition struct {
+	Name          string       // パーティション名
+	Bound         string       // 値の範囲（LESS THAN (100) / IN (1,2) / FROM (...) TO (...) など。HASH / KEY の場合は空）
+	Rows          *int64       // 推定行数（取得できない場合はnil）
+	Comment       string       // コメント
+	Subpartitions []*Partition // サブパーティション（定義順）
+}
+
+// Trigger はテーブルのトリガー情報を表します。
+type Trigger struct {
+	Name      string // トリガー名
+	Timing    string // 起動タイミング（BEFORE / AFTER / INSTEAD OF）
+	Event     string // 起動するイベント（INSERT / UPDATE / DELETE。複数の場合は OR 区切り）
+	Order     int64  // 同じタイミング・イベントのトリガー内での実行順
+	Statement string // トリガーの本体
+	Definer   string // 定義者（MySQL のみ）
+}
+
+// Routine はストアドプロシージャ・ストアドファンクションの情報を表します。
+type Routine struct {
+	Schema          string       // スキーマ名（スキーマを持つDBエンジンのみ）
+	Name            string       // ルーチン名
+	Type            string       // 種類（PROCEDURE / FUNCTION）
+	Parameters      []*Parameter // 引数（定義順）
+	ReturnType      string       // 戻り値の型（FUNCTION のみ）
+	Language        string       // 記述言語（SQL / PLPGSQL など）
+	Definition      string       // 本体の定義
+	SecurityType    string       // 実行時の権限（DEFINER / INVOKER）
+	IsDeterministic bool         // 同じ引数に対して常に同じ結果を返すか
+	DataAccess      string       // SQLデータへのアクセス（CONTAINS SQL / NO SQL / READS SQL DATA / MODIFIES SQL DATA）
+	Definer         string       // 定義者（MySQL のみ）
+	Comment         string       // コメント
+}
+
+// Parameter はルーチンの引数を表します。
+type Parameter struct {
+	Name     string // 引数名
+	Mode     string // 入出力の区分（IN / OUT / INOUT / VARIADIC）
+	DataType string // データ型
+}
+
+// Event はイベントスケジューラに登録されたイベントの情報を表します。
+type Event struct {
+	Schema        string     // スキーマ名
+	Name          string     // イベント名
+	Definition    string     // 実行するSQL
+	Type          string     // 種類（ONE TIME / RECURRING）
+	ExecuteAt     *time.Time // 実行日時（ONE TIME のみ）
+	IntervalValue string     // 実行間隔（RECURRING のみ）
+	IntervalField string     // 実行間隔の単位（DAY / HOUR など）
+	Starts        *time.Time // 開始日時（RECURRING のみ）
+	Ends          *time.Time // 終了日時（指定がない場合はnil）
+	Status        string     // 状態（ENABLED / DISABLED / SLAVESIDE_DISABLED）
+	OnCompletion  string     // 終了後の扱い（PRESERVE / NOT PRESERVE）
+	Definer       string     // 定義者
+	Comment       string     // コメント
+}
+
+// Sequence はデータベースのシーケンス情報を表します。
+type Sequence struct {
+	Schema    string // スキーマ名
+	Name      string // シーケンス名
+	DataType  string // データ型
+	Start     int64  // 開始値
+	Increment int64  // 増分
+	MinValue  int64  // 最小値
+	MaxValue  int64  // 最大値
+	Cycle     bool   // 最大値到達時に循環するか
+	OwnedBy   string // 所有しているカラム（テーブル名.カラム名）
+}
+
+// Grant はユーザーに付与された権限を表します。権限の種類ごとに1つの Grant とします。
+type Grant struct {
+	Grantee     string // 権限を付与されたユーザー（'user'@'host' の形式）
+	Level       string // 権限の範囲（GLOBAL / SCHEMA / TABLE / COLUMN）
+	Schema      string // 対象のスキーマ（GLOBAL の場合は空。SCHEMA の場合はワイルドカードを含む場合がある）
+	Table       string // 対象のテーブル（TABLE / COLUMN のみ）
+	Column      string // 対象のカラム（COLUMN のみ）
+	Privilege   string // 権限の種類（SELECT / INSERT / UPDATE など）
+	IsGrantable bool   // 他のユーザーに権限を付与できるか（WITH GRANT OPTION）
+}
+
+// IndexReport は削除を検討できるインデックス（使われていない・他のインデックスに包含される）を表します。
+type IndexReport struct {
+	Table           string // テーブル名
+	Index           string // インデックス名
+	Reason          string // 理由（UNUSED: 読み取りに使われていない / REDUNDANT: 他のインデックスに包含される）
+	Columns         string // インデックスを構成するカラム（カンマ区切り）
+	DominantIndex   string // 包含しているインデックス名（REDUNDANT のみ）
+	DominantColumns string // 包含しているインデックスのカラム（REDUNDANT のみ）
+	ReadCount       *int64 // サーバー起動後にインデックスを使って読み取った行数（取得できない場合はnil）
+	DropStatement   string // インデックスを削除するSQL
 }
@@ -1 +1,67 @@
 package csv
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile は見出し行とレコードをCSVファイルへ書き込みます。親ディレクトリが存在しない場合は作成します。
+func WriteFile(path string, headers []string, records [][]string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	writer := csv.NewWriter(f)
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// ReadFile はCSVファイルを読み込み、見出し行を除いた各レコードを見出し名をキーとするマップで返します。
+// ファイルが存在しない場合は空のスライスを返します。
+func ReadFile(path string) ([]map[string]string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	headers := records[0]
+	var rows []map[string]string
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(headers))
+		for i, header := range headers {
+			if i < len(record) {
+				row[header] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -1,14 +1,133 @@
 package mysql
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
 	"fmt"
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"strings"
 )
 
-// Connect はMySQLデータベースへの接続を確立します。
-func Connect(username, password, hostname, port, dbname string) (*sql.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", username, password, hostname, port, dbname)
-	fmt.Println(dsn)
-	return sql.Open("mysql", dsn)
+// Options はMySQLへの接続設定です。
+type Options struct {
+	Username string
+	Password string
+	Host     string
+	Port     string
+	Database string
+	Socket   string      // UNIXソケットのパス（指定した場合は Host・Port より優先）
+	TLS      *TLSOptions // TLSの設定（nil の場合はTLSを使わない）
+	Params   url.Values  // DSNに追加するドライバのパラメータ（timeout・charset・システム変数など）
+	DSN      string      // 指定した場合は上記の設定の代わりにこのDSNで接続する
+}
+
+// TLSOptions はTLS接続の設定です。
+type TLSOptions struct {
+	CAFile     string // サーバー証明書の検証に使うCA証明書のファイル
+	CertFile   string // クライアント証明書のファイル
+	KeyFile    string // クライアント証明書の秘密鍵のファイル
+	SkipVerify bool   // サーバー証明書を検証しない（開発環境向け）
+}
+
+// Connect はMySQLデータベースへの接続を確立します。接続先はパスワードを伏せてログに出力します。
+func Connect(opts Options) (*sql.DB, error) {
+	cfg, err := opts.config()
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("connecting to mysql: %s (%s)", Redact(cfg), tlsSummary(cfg))
+	return sql.OpenDB(connector), nil
+}
+
+// config は接続設定からドライバの設定を作成します。
+// 日時のカラムを time.Time として読み取るため、parseTime は常に有効にします。
+func (opts Options) config() (*mysql.Config, error) {
+	dsn := opts.DSN
+	if dsn == "" {
+		base := mysql.NewConfig()
+		base.User = opts.Username
+		base.Passwd = opts.Password
+		base.DBName = opts.Database
+		if opts.Socket != "" {
+			base.Net, base.Addr = "unix", opts.Socket
+		} else {
+			base.Net, base.Addr = "tcp", net.JoinHostPort(opts.Host, opts.Port)
+		}
+		dsn = base.FormatDSN()
+		// パラメータはドライバの解釈（timeout などの設定項目とシステム変数の区別）に任せるため、DSNに付けて解析する
+		if len(opts.Params) > 0 {
+			separator := "?"
+			if strings.Contains(dsn, "?") {
+				separator = "&"
+			}
+			dsn += separator + opts.Params.Encode()
+		}
+	}
+
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mysql DSN: %w", err)
+	}
+	cfg.ParseTime = true
+
+	if opts.TLS != nil {
+		if cfg.TLS, err = opts.TLS.config(); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// config はTLSの設定からクライアントのTLS設定を作成します。
+func (opts *TLSOptions) config() (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: opts.SkipVerify}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", opts.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// tlsSummary はTLSの有無とサーバー証明書の検証の有無を返します。
+// コードで設定したTLSはDSNに現れないため、接続先のログに添えて出力します。
+func tlsSummary(cfg *mysql.Config) string {
+	if cfg.TLS == nil {
+		return "tls=off"
+	}
+	return fmt.Sprintf("tls=on, skip-verify=%t", cfg.TLS.InsecureSkipVerify)
+}
+
+// Redact はパスワードを伏せたDSNを返します。
+func Redact(cfg *mysql.Config) string {
+	redacted := cfg.Clone()
+	if redacted.Passwd != "" {
+		redacted.Passwd = "xxxxx"
+	}
+	return redacted.FormatDSN()
 }
@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"crypto/tls"
+	"github.com/go-sql-driver/mysql"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TLSの指定方法にかかわらずパスワードを伏せ、元の設定を変更しないこと
+func TestRedact(t *testing.T) {
+	if err := mysql.RegisterTLSConfig("redact-test", &tls.Config{ServerName: "db"}); err != nil {
+		t.Fatal(err)
+	}
+	defer mysql.DeregisterTLSConfig("redact-test")
+
+	tests := []struct {
+		name     string
+		opts     Options
+		password string
+		want     string
+		tls      string
+	}{
+		{
+			name:     "options",
+			opts:     Options{Username: "u", Password: "p@ss:w/rd?", Host: "h", Port: "3306", Database: "db"},
+			password: "p@ss:w/rd?",
+			want:     "u:xxxxx@tcp(h:3306)/db?parseTime=true",
+			tls:      "tls=off",
+		},
+		{
+			name:     "skip-verify",
+			opts:     Options{DSN: "u:secret@tcp(h:3306)/db?tls=skip-verify&timeout=5s"},
+			password: "secret",
+			want:     "u:xxxxx@tcp(h:3306)/db?parseTime=true&timeout=5s&tls=skip-verify",
+			tls:      "tls=on, skip-verify=true",
+		},
+		{
+			name:     "tls true",
+			opts:     Options{DSN: "u:secret@tcp(h:3306)/db?tls=true"},
+			password: "secret",
+			want:     "u:xxxxx@tcp(h:3306)/db?parseTime=true&tls=true",
+			tls:      "tls=on, skip-verify=false",
+		},
+		{
+			name:     "preferred",
+			opts:     Options{DSN: "u:secret@tcp(h:3306)/db?tls=preferred"},
+			password: "secret",
+			want:     "u:xxxxx@tcp(h:3306)/db?allowFallbackToPlaintext=true&parseTime=true&tls=preferred",
+			tls:      "tls=on, skip-verify=true",
+		},
+		{
+			name:     "registered",
+			opts:     Options{DSN: "u:tls=secret@tcp(h:3306)/db?tls=redact-test"},
+			password: "tls=secret",
+			want:     "u:xxxxx@tcp(h:3306)/db?parseTime=true&tls=redact-test",
+			tls:      "tls=on, skip-verify=false",
+		},
+		{
+			name:     "tls options",
+			opts:     Options{Username: "u", Password: "secret", Host: "h", Port: "3306", Database: "db", TLS: &TLSOptions{SkipVerify: true}},
+			password: "secret",
+			want:     "u:xxxxx@tcp(h:3306)/db?parseTime=true",
+			tls:      "tls=on, skip-verify=true",
+		},
+		{
+			name: "tls params",
+			opts: Options{
+				Username: "u", Password: "secret", Socket: "/tmp/mysql.sock", Database: "db",
+				Params: url.Values{"tls": {"skip-verify"}},
+			},
+			password: "secret",
+			want:     "u:xxxxx@unix(/tmp/mysql.sock)/db?parseTime=true&tls=skip-verify",
+			tls:      "tls=on, skip-verify=true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.opts.config()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Redact(cfg)
+			if strings.Contains(got, tt.password) {
+				t.Errorf("%q contains password %q", got, tt.password)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if summary := tlsSummary(cfg); summary != tt.tls {
+				t.Errorf("got %q, want %q", summary, tt.tls)
+			}
+			if cfg.Passwd != tt.password {
+				t.Errorf("Redact changed the password of the original config to %q", cfg.Passwd)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/lib/pq"
+	"log"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Options はPostgreSQLへの接続設定です。
+type Options struct {
+	Username    string
+	Password    string
+	Host        string
+	Port        string
+	Database    string
+	Socket      string     // UNIXソケットのディレクトリ（指定した場合は Host より優先）
+	SSLMode     string     // SSLモード（未指定の場合は disable）
+	SSLRootCert string     // サーバー証明書の検証に使うCA証明書のファイル
+	SSLCert     string     // クライアント証明書のファイル
+	SSLKey      string     // クライアント証明書の秘密鍵のファイル
+	Params      url.Values // 接続文字列に追加するパラメータ（connect_timeout・application_name など）
+	DSN         string     // 指定した場合は上記の設定の代わりにこの接続文字列で接続する
+}
+
+// Connect はPostgreSQLデータベースへの接続を確立します。接続先はパスワードを伏せてログに出力します。
+func Connect(opts Options) (*sql.DB, error) {
+	dsn := opts.DSN
+	if dsn == "" {
+		dsn = opts.url().String()
+	}
+
+	log.Printf("connecting to postgres: %s", Redact(dsn))
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, redactError(dsn, err)
+	}
+	return sql.OpenDB(redactingConnector{Connector: connector, dsn: dsn}), nil
+}
+
+// redactingConnector は接続時のエラーからパスワードを伏せる driver.Connector です。
+type redactingConnector struct {
+	driver.Connector
+	dsn string
+}
+
+func (c redactingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, redactError(c.dsn, err)
+	}
+	return conn, nil
+}
+
+// connectError はパスワードを伏せた接続文字列とメッセージを持つ接続時のエラーです。
+type connectError struct {
+	dsn     string
+	message string
+	err     error
+}
+
+func (e *connectError) Error() string {
+	return fmt.Sprintf("connect to postgres %s: %s", e.dsn, e.message)
+}
+
+func (e *connectError) Unwrap() error {
+	return e.err
+}
+
+// urlPasswordPattern は postgres:// 形式の接続文字列のパスワードです。
+var urlPasswordPattern = regexp.MustCompile(`^(postgres(?:ql)?://[^:@/]*:)([^@]*)@`)
+
+// queryPasswordPattern は postgres:// 形式の接続文字列のパラメータで指定したパスワードです。
+var queryPasswordPattern = regexp.MustCompile(`([?&]password=)([^&]*)`)
+
+// redactError は接続文字列 dsn のパスワードを伏せたエラーを返します。
+// url.Parse のエラーなどはメッセージに接続文字列をそのまま含むため、メッセージからもパスワードを取り除きます。
+func redactError(dsn string, err error) error {
+	var passwords []string
+	if m := urlPasswordPattern.FindStringSubmatch(dsn); m != nil {
+		passwords = append(passwords, m[2])
+		if decoded, err := url.PathUnescape(m[2]); err == nil {
+			passwords = append(passwords, decoded)
+		}
+	}
+	for _, m := range queryPasswordPattern.FindAllStringSubmatch(dsn, -1) {
+		passwords = append(passwords, m[2])
+		if decoded, err := url.QueryUnescape(m[2]); err == nil {
+			passwords = append(passwords, decoded)
+		}
+	}
+	for _, m := range keywordPasswordPattern.FindAllStringSubmatch(dsn, -1) {
+		passwords = append(passwords, m[2], strings.Trim(m[2], "'"))
+	}
+
+	message := err.Error()
+	for _, password := range passwords {
+		if password != "" {
+			message = strings.ReplaceAll(message, password, "xxxxx")
+		}
+	}
+	return &connectError{dsn: Redact(dsn), message: message, err: err}
+}
+
+// url は接続設定から postgres:// 形式の接続文字列を作成します。
+func (opts Options) url() *url.URL {
+	sslMode := opts.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	query := url.Values{"sslmode": {sslMode}}
+	for key, value := range map[string]string{
+		"sslrootcert": opts.SSLRootCert,
+		"sslcert":     opts.SSLCert,
+		"sslkey":      opts.SSLKey,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	for key, values := range opts.Params {
+		query[key] = values
+	}
+
+	dsn := &url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(opts.Username, opts.Password),
+		Path:   "/" + opts.Database,
+	}
+	if opts.Socket != "" {
+		// UNIXソケットはディレクトリを host パラメータで指定する
+		query.Set("host", opts.Socket)
+		if opts.Port != "" {
+			query.Set("port", opts.Port)
+		}
+	} else {
+		dsn.Host = net.JoinHostPort(opts.Host, opts.Port)
+	}
+	dsn.RawQuery = query.Encode()
+	return dsn
+}
+
+// keywordPasswordPattern は「key=value」形式の接続文字列のパスワードです。
+var keywordPasswordPattern = regexp.MustCompile(`(?i)(\bpassword\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// Redact はパスワードを伏せた接続文字列を返します。
+func Redact(dsn string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		if u, err := url.Parse(dsn); err == nil {
+			if query := u.Query(); query.Has("password") {
+				query.Set("password", "xxxxx")
+				u.RawQuery = query.Encode()
+			}
+			return u.Redacted()
+		}
+		dsn = urlPasswordPattern.ReplaceAllString(dsn, "${1}xxxxx@")
+		return queryPasswordPattern.ReplaceAllString(dsn, "${1}xxxxx")
+	}
+	return keywordPasswordPattern.ReplaceAllString(dsn, "${1}xxxxx")
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/lib/pq"
+	"strings"
+	"testing"
+)
+
+// 接続文字列の形式にかかわらずパスワードを伏せること
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name      string
+		dsn       string
+		passwords []string
+		want      string
+	}{
+		{
+			name:      "url",
+			dsn:       "postgres://u:secret@h:5432/db?sslmode=disable",
+			passwords: []string{"secret"},
+			want:      "postgres://u:xxxxx@h:5432/db?sslmode=disable",
+		},
+		{
+			name:      "escaped password",
+			dsn:       "postgres://u:p%40ss@h/db",
+			passwords: []string{"p%40ss", "p@ss"},
+			want:      "postgres://u:xxxxx@h/db",
+		},
+		{
+			name:      "query password",
+			dsn:       "postgresql://u@h/db?sslmode=require&password=s%26cret",
+			passwords: []string{"s%26cret", "s&cret"},
+			want:      "postgresql://u@h/db?password=xxxxx&sslmode=require",
+		},
+		{
+			name:      "url and query password",
+			dsn:       "postgres://u:first@h/db?password=second",
+			passwords: []string{"first", "second"},
+		},
+		{
+			name:      "invalid url",
+			dsn:       "postgres://u:pa ss@h:port/db?password=other",
+			passwords: []string{"pa ss", "other"},
+			want:      "postgres://u:xxxxx@h:port/db?password=xxxxx",
+		},
+		{
+			name:      "keyword",
+			dsn:       "host=h user=u password=secret dbname=db",
+			passwords: []string{"secret"},
+			want:      "host=h user=u password=xxxxx dbname=db",
+		},
+		{
+			name:      "quoted keyword",
+			dsn:       "host=h password='a b c' dbname=db",
+			passwords: []string{"a b c"},
+			want:      "host=h password=xxxxx dbname=db",
+		},
+		{
+			name:      "escaped quote in keyword",
+			dsn:       `host=h password = 'it\'s secret' dbname=db`,
+			passwords: []string{"it", "secret"},
+			want:      "host=h password = xxxxx dbname=db",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Redact(tt.dsn)
+			for _, password := range tt.passwords {
+				if strings.Contains(got, password) {
+					t.Errorf("%q contains password %q", got, password)
+				}
+			}
+			if tt.want != "" && got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ドライバのエラーメッセージが接続文字列やパスワードをそのまま含む場合も伏せること
+func TestRedactError(t *testing.T) {
+	tests := []struct {
+		name      string
+		dsn       string
+		err       error
+		passwords []string
+	}{
+		{
+			name:      "escaped password echoed",
+			dsn:       "postgres://u:p%40ss@h/db",
+			err:       errors.New(`cannot connect to "postgres://u:p%40ss@h/db" as p@ss`),
+			passwords: []string{"p%40ss", "p@ss"},
+		},
+		{
+			name:      "query password echoed",
+			dsn:       "postgres://u@h/db?password=s%26cret",
+			err:       errors.New("bad option password=s&cret in postgres://u@h/db?password=s%26cret"),
+			passwords: []string{"s%26cret", "s&cret"},
+		},
+		{
+			name:      "quoted keyword echoed",
+			dsn:       "host=h password='a b c' dbname=db",
+			err:       errors.New("invalid connection string: host=h password='a b c' dbname=db"),
+			passwords: []string{"a b c"},
+		},
+		{
+			name:      "driver error with invalid escape",
+			dsn:       "postgres://u:s3cr%zz@h/db",
+			err:       connectorError(t, "postgres://u:s3cr%zz@h/db"),
+			passwords: []string{"s3cr%zz"},
+		},
+		{
+			name:      "driver error with invalid port",
+			dsn:       "postgres://u:pa ss@h:port/db",
+			err:       connectorError(t, "postgres://u:pa ss@h:port/db"),
+			passwords: []string{"pa ss"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactError(tt.dsn, tt.err)
+			for _, password := range tt.passwords {
+				if strings.Contains(got.Error(), password) {
+					t.Errorf("%q contains password %q", got.Error(), password)
+				}
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("redacted error does not wrap %v", tt.err)
+			}
+		})
+	}
+}
+
+// connectorError は接続文字列の解析でドライバが返すエラーです。
+func connectorError(t *testing.T, dsn string) error {
+	t.Helper()
+	_, err := pq.NewConnector(dsn)
+	if err == nil {
+		t.Fatalf("pq.NewConnector(%q) succeeded", dsn)
+	}
+	return err
+}
@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// Connect はSQLiteデータベースファイルへ読み取り専用で接続します。
+func Connect(path string) (*sql.DB, error) {
+	// 存在しないパスを指定した場合に空のデータベースが作成されないよう事前に確認
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	// ファイル名の「?」「#」「%」などがURIの区切りと解釈されないよう、絶対パスをエスケープしてURIにする
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	dsn := (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs), RawQuery: "mode=ro"}).String()
+	return sql.Open("sqlite3", dsn)
+}
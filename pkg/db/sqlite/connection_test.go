@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ファイル名にURIの区切り文字を含むデータベースにも読み取り専用で接続できること
+func TestConnect(t *testing.T) {
+	for _, name := range []string{"plain.db", "what?.db", "no#1.db", "100%.db", "a b&c=d.db"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "src.db")
+			db, err := sql.Open("sqlite3", src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := db.Exec("CREATE TABLE users (id integer)"); err != nil {
+				t.Fatal(err)
+			}
+			db.Close()
+			path := filepath.Join(dir, name)
+			if err := os.Rename(src, path); err != nil {
+				t.Fatal(err)
+			}
+
+			db, err = Connect(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			var table string
+			if err := db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'table'").Scan(&table); err != nil {
+				t.Fatal(err)
+			}
+			if table != "users" {
+				t.Errorf("got table %q, want %q", table, "users")
+			}
+			if _, err := db.Exec("CREATE TABLE posts (id integer)"); err == nil {
+				t.Error("write succeeded on a read-only connection")
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("got %d files in %s, want only %s", len(entries), dir, name)
+			}
+		})
+	}
+}
+
+// 存在しないファイルを指定した場合はエラーにし、空のデータベースを作成しないこと
+func TestConnectMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing?.db")
+	if _, err := Connect(path); !os.IsNotExist(err) {
+		t.Errorf("got error %v, want not exist", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s was created", path)
+	}
+}